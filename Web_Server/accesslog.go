@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// accessLog is the optional Combined Log Format writer, enabled by
+// IRIS_ACCESS_LOG ("-" or "stdout" for standard out, anything else a
+// file path, appended). The structured log keeps going to stderr;
+// this one exists so GoAccess, awstats, and friends can eat the
+// output unmodified. SIGHUP reopens the file, which is all logrotate
+// needs.
+var accessLog *accessLogger
+
+type accessLogger struct {
+	mu   sync.Mutex
+	path string // empty when writing to stdout
+	f    *os.File
+}
+
+// newAccessLogger opens the access log destination, or returns nil
+// when none is configured.
+func newAccessLogger() (*accessLogger, error) {
+	switch path := os.Getenv("IRIS_ACCESS_LOG"); path {
+	case "":
+		return nil, nil
+	case "-", "stdout":
+		return &accessLogger{f: os.Stdout}, nil
+	default:
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("opening access log: %w", err)
+		}
+		return &accessLogger{path: path, f: f}, nil
+	}
+}
+
+// Reopen closes and reopens the log file so a rotated file is
+// released. A stdout logger has nothing to do.
+func (l *accessLogger) Reopen() error {
+	if l.path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("reopening access log: %w", err)
+	}
+	l.mu.Lock()
+	old := l.f
+	l.f = f
+	l.mu.Unlock()
+	return old.Close()
+}
+
+// line writes one Combined Log Format record:
+//
+//	host - user [time] "request" status bytes "referer" "user-agent"
+func (l *accessLogger) line(r *http.Request, status int, bytes int64, when time.Time) {
+	user := "-"
+	if u, _, ok := r.BasicAuth(); ok && u != "" {
+		user = u
+	}
+	size := "-"
+	if bytes > 0 {
+		size = strconv.FormatInt(bytes, 10)
+	}
+	record := fmt.Sprintf("%s - %s [%s] %q %d %s %q %q\n",
+		clientIP(r),
+		user,
+		when.Format("02/Jan/2006:15:04:05 -0700"),
+		r.Method+" "+r.RequestURI+" "+r.Proto,
+		status,
+		size,
+		r.Referer(),
+		r.UserAgent(),
+	)
+	l.mu.Lock()
+	l.f.WriteString(record)
+	l.mu.Unlock()
+}
+
+// withAccessLog logs every request as it completes. It sits outside
+// the recovery middleware so even a panic-turned-500 leaves a record.
+func withAccessLog(l *accessLogger, next http.Handler) http.Handler {
+	if l == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		cw := &countingWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() { l.line(r, cw.status, cw.bytes, start) }()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// countingWriter captures the status and body size for the log line.
+type countingWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (cw *countingWriter) WriteHeader(status int) {
+	cw.status = status
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.ResponseWriter.Write(p)
+	cw.bytes += int64(n)
+	return n, err
+}
+
+func (cw *countingWriter) Flush() {
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/divijg19/Iris/Web_Server/internal/auth"
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+	"github.com/divijg19/Iris/Web_Server/internal/timing"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// authn guards the user CRUD routes when configured; nil leaves the
+// API open, which keeps existing deployments working.
+var authn *auth.Authenticator
+
+// refreshes holds the server-side refresh tokens paired with authn;
+// it follows authn's enabled/disabled state.
+var refreshes *auth.RefreshStore
+
+// sessions, when non-nil, replaces JWTs with cookie sessions for
+// clients that cannot handle bearer tokens.
+var sessions *auth.Sessions
+
+// newSessions builds the cookie-session subsystem when IRIS_AUTH is
+// "session". IRIS_SESSION_REDIS points it at Redis (in-memory
+// otherwise), IRIS_SESSION_TTL sets the lifetime, and
+// IRIS_SESSION_INSECURE drops the Secure cookie flag for local
+// plain-HTTP development.
+func newSessions() (*auth.Sessions, error) {
+	if os.Getenv("IRIS_AUTH") != "session" {
+		return nil, nil
+	}
+	ttl := time.Duration(0)
+	if v := os.Getenv("IRIS_SESSION_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid IRIS_SESSION_TTL %q", v)
+		}
+		ttl = d
+	}
+	var store auth.SessionStore = auth.NewMemorySessions()
+	if addr := os.Getenv("IRIS_SESSION_REDIS"); addr != "" {
+		rs, err := auth.NewRedisSessions(context.Background(), addr)
+		if err != nil {
+			return nil, fmt.Errorf("connecting to session redis: %w", err)
+		}
+		store = rs
+	}
+	s := auth.NewSessions(store, ttl)
+	if os.Getenv("IRIS_SESSION_INSECURE") == "true" {
+		s.Secure = false
+	}
+	return s, nil
+}
+
+// newAuthenticator builds the JWT authenticator from the environment:
+// IRIS_JWT_SECRET enables HS256, IRIS_JWT_KEY (an RSA private key PEM
+// file) enables RS256, IRIS_JWT_TTL overrides the access-token
+// lifetime. With neither key source set, auth stays disabled.
+func newAuthenticator() (*auth.Authenticator, error) {
+	cfg := auth.Config{}
+	if v := os.Getenv("IRIS_JWT_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid IRIS_JWT_TTL %q", v)
+		}
+		cfg.AccessTTL = d
+	}
+	switch {
+	case os.Getenv("IRIS_JWT_KEY") != "":
+		raw, err := os.ReadFile(os.Getenv("IRIS_JWT_KEY"))
+		if err != nil {
+			return nil, fmt.Errorf("reading IRIS_JWT_KEY: %w", err)
+		}
+		block, _ := pem.Decode(raw)
+		if block == nil {
+			return nil, errors.New("IRIS_JWT_KEY is not PEM")
+		}
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing IRIS_JWT_KEY: %w", err)
+		}
+		cfg.Alg = "RS256"
+		cfg.PrivateKey = key
+		cfg.PublicKey = &key.PublicKey
+	case os.Getenv("IRIS_JWT_SECRET") != "":
+		cfg.Alg = "HS256"
+		cfg.Secret = []byte(os.Getenv("IRIS_JWT_SECRET"))
+	default:
+		return nil, nil
+	}
+	return auth.New(cfg)
+}
+
+// protected wraps h with whichever auth middleware is configured:
+// cookie sessions take precedence, then JWT, else the route is open.
+func protected(h http.HandlerFunc) http.HandlerFunc {
+	var mw func(http.HandlerFunc) http.HandlerFunc
+	switch {
+	case sessions != nil:
+		mw = sessions.Middleware
+	case authn != nil:
+		mw = authn.Middleware
+	default:
+		return h
+	}
+	// The "auth" Server-Timing stage covers exactly the middleware's
+	// own work: it opens before token verification and closes when the
+	// handler takes over.
+	return func(w http.ResponseWriter, r *http.Request) {
+		stop := timing.Stage(r.Context(), "auth")
+		mw(func(w http.ResponseWriter, r *http.Request) {
+			stop()
+			h(w, r)
+		})(w, r)
+	}
+}
+
+// scopesFor decides what an authenticated user may do: everyone gets
+// read and write on users, and addresses listed in IRIS_ADMIN_EMAILS
+// (comma-separated) also get admin.
+func scopesFor(u models.User) []string {
+	scopes := []string{"users:read", "users:write"}
+	for _, admin := range strings.Split(os.Getenv("IRIS_ADMIN_EMAILS"), ",") {
+		if admin = strings.TrimSpace(admin); admin != "" && strings.EqualFold(admin, u.Email) {
+			return append(scopes, "admin")
+		}
+	}
+	return scopes
+}
+
+// requireScope gates h behind a token scope. With authentication
+// disabled the API stays open, scopes included.
+func requireScope(scope string, h http.HandlerFunc) http.HandlerFunc {
+	if sessions == nil && authn == nil {
+		return h
+	}
+	return auth.RequireScope(scope)(h)
+}
+
+// hashPassword bcrypt-hashes a plaintext password for storage.
+func hashPassword(plain string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// scrubUser strips credential material before a user goes out in an
+// API response.
+func scrubUser(u models.User) models.User {
+	u.Password = ""
+	u.PasswordHash = ""
+	return u
+}
+
+// loginUser handles POST /auth/login: it verifies the email/password
+// pair against the store and answers with a signed access token.
+func loginUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if authn == nil && sessions == nil {
+		apiError(w, r, http.StatusNotImplemented, "authentication is not configured")
+		return
+	}
+	var creds struct {
+		Email    string `json:"email"`
+		Password string `json:"password"`
+	}
+	if !decodeJSON(w, r, &creds) {
+		return
+	}
+
+	u, err := users.GetByEmail(r.Context(), creds.Email)
+	if err != nil && !errors.Is(err, store.ErrNotFound) {
+		apiError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	// Same answer whether the account or the password is wrong, so
+	// login cannot be used to probe for registered emails.
+	if err != nil || u.PasswordHash == "" ||
+		bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(creds.Password)) != nil {
+		apiError(w, r, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	issueCredentials(w, r, u)
+}
+
+// issueCredentials answers a successful authentication with whatever
+// credential style is configured: a session cookie (204) or a JWT
+// access/refresh pair.
+func issueCredentials(w http.ResponseWriter, r *http.Request, u models.User) {
+	id := auth.Identity{UserID: u.ID, Email: u.Email, Scopes: scopesFor(u)}
+	if sessions != nil {
+		if err := sessions.Login(r.Context(), w, id); err != nil {
+			log.Printf("issueCredentials: creating session: %v", err)
+			apiError(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	token, err := authn.Issue(u.ID, u.Email, id.Scopes...)
+	if err != nil {
+		log.Printf("issueCredentials: issuing token: %v", err)
+		apiError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	refresh, err := refreshes.Issue(id)
+	if err != nil {
+		log.Printf("issueCredentials: issuing refresh token: %v", err)
+		apiError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{
+		"access_token":  token,
+		"refresh_token": refresh,
+		"token_type":    "Bearer",
+		"expires_in":    int(authn.AccessTTL().Seconds()),
+	})
+}
+
+// refreshAuth handles POST /auth/refresh: it rotates the presented
+// refresh token and answers with a fresh access/refresh pair. A token
+// that was already rotated revokes its whole family.
+func refreshAuth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if authn == nil {
+		apiError(w, r, http.StatusNotImplemented, "authentication is not configured")
+		return
+	}
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	next, id, err := refreshes.Rotate(body.RefreshToken)
+	if err != nil {
+		apiError(w, r, http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
+	access, err := authn.Issue(id.UserID, id.Email, id.Scopes...)
+	if err != nil {
+		log.Printf("refreshAuth: issuing token: %v", err)
+		apiError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	respond.JSON(w, http.StatusOK, map[string]any{
+		"access_token":  access,
+		"refresh_token": next,
+		"token_type":    "Bearer",
+		"expires_in":    int(authn.AccessTTL().Seconds()),
+	})
+}
+
+// logoutUser handles POST /auth/logout: it destroys the cookie
+// session in session mode, or revokes the refresh token family in JWT
+// mode.
+func logoutUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if sessions != nil {
+		if err := sessions.Logout(w, r); err != nil {
+			log.Printf("logoutUser: %v", err)
+			apiError(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if authn == nil {
+		apiError(w, r, http.StatusNotImplemented, "authentication is not configured")
+		return
+	}
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	refreshes.Revoke(body.RefreshToken)
+	w.WriteHeader(http.StatusNoContent)
+}
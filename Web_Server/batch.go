@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Guard rails for a batch call: how many sub-requests one body may
+// carry and how many may run at once.
+const (
+	batchMaxItems       = 50
+	batchMaxConcurrency = 8
+)
+
+// batchItem is one sub-request in a POST /batch body.
+type batchItem struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// batchResult mirrors one sub-response, in the same order as the
+// request items.
+type batchResult struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// batchRecorder captures a sub-response in memory so it can be folded
+// into the batch reply.
+type batchRecorder struct {
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (rec *batchRecorder) Header() http.Header {
+	if rec.header == nil {
+		rec.header = http.Header{}
+	}
+	return rec.header
+}
+
+func (rec *batchRecorder) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(p)
+}
+
+func (rec *batchRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+// batchHandler serves POST /batch: it decodes an array of sub-requests
+// and replays them through the same route table the network uses, so
+// every sub-request passes the usual auth, limits, and validation.
+// ?concurrency=N (capped at batchMaxConcurrency) runs items in
+// parallel; the default of 1 preserves sequential semantics for
+// clients that chain writes.
+func batchHandler(next http.Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		var items []batchItem
+		if !decodeJSON(w, r, &items) {
+			return
+		}
+		if len(items) == 0 {
+			apiError(w, r, http.StatusBadRequest, "empty batch")
+			return
+		}
+		if len(items) > batchMaxItems {
+			apiError(w, r, http.StatusRequestEntityTooLarge, "batch exceeds "+strconv.Itoa(batchMaxItems)+" items")
+			return
+		}
+		for i, item := range items {
+			if !strings.HasPrefix(item.Path, "/") || item.Path == "/batch" {
+				apiError(w, r, http.StatusBadRequest, "item "+strconv.Itoa(i)+": invalid path")
+				return
+			}
+		}
+
+		concurrency := 1
+		if v := r.URL.Query().Get("concurrency"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 1 {
+				apiError(w, r, http.StatusBadRequest, "invalid concurrency")
+				return
+			}
+			concurrency = min(n, batchMaxConcurrency)
+		}
+
+		results := make([]batchResult, len(items))
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, concurrency)
+		for i, item := range items {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, item batchItem) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = runBatchItem(r, next, item)
+			}(i, item)
+		}
+		wg.Wait()
+
+		respond.JSON(w, http.StatusOK, results)
+	}
+}
+
+// runBatchItem replays one sub-request in process, inheriting the
+// caller's credentials so scope checks apply per item.
+func runBatchItem(parent *http.Request, next http.Handler, item batchItem) batchResult {
+	sub, err := http.NewRequestWithContext(parent.Context(), item.Method, item.Path, bytes.NewReader(item.Body))
+	if err != nil {
+		return batchResult{Status: http.StatusBadRequest}
+	}
+	sub.RemoteAddr = parent.RemoteAddr
+	for _, h := range []string{"Authorization", "Cookie", "Accept-Language"} {
+		if v := parent.Header.Get(h); v != "" {
+			sub.Header.Set(h, v)
+		}
+	}
+	if len(item.Body) > 0 {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+
+	rec := &batchRecorder{}
+	next.ServeHTTP(rec, sub)
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+
+	res := batchResult{Status: rec.status}
+	if raw := rec.body.Bytes(); len(raw) > 0 {
+		if json.Valid(raw) {
+			res.Body = json.RawMessage(bytes.TrimSpace(raw))
+		} else {
+			res.Body, _ = json.Marshal(string(raw))
+		}
+	}
+	return res
+}
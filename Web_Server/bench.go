@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// benchBackends maps a backend name to a constructor returning the
+// store and a cleanup function. Backends register here as they are
+// implemented so benchstore automatically covers them.
+var benchBackends = map[string]func() (store.Store, func() error, error){
+	"memory": func() (store.Store, func() error, error) {
+		return store.NewMemory(), func() error { return nil }, nil
+	},
+	"sqlite": func() (store.Store, func() error, error) {
+		dir, err := os.MkdirTemp("", "iris-bench-sqlite-")
+		if err != nil {
+			return nil, nil, err
+		}
+		st, err := store.NewSQLite(filepath.Join(dir, "bench.db"))
+		if err != nil {
+			os.RemoveAll(dir)
+			return nil, nil, err
+		}
+		return st, func() error {
+			st.Close()
+			return os.RemoveAll(dir)
+		}, nil
+	},
+	"bolt": func() (store.Store, func() error, error) {
+		dir, err := os.MkdirTemp("", "iris-bench-bolt-")
+		if err != nil {
+			return nil, nil, err
+		}
+		st, err := store.NewBolt(filepath.Join(dir, "bench.bolt"))
+		if err != nil {
+			os.RemoveAll(dir)
+			return nil, nil, err
+		}
+		return st, func() error {
+			st.Close()
+			return os.RemoveAll(dir)
+		}, nil
+	},
+	"mysql": func() (store.Store, func() error, error) {
+		dsn := os.Getenv("IRIS_BENCH_MYSQL_DSN")
+		if dsn == "" {
+			return nil, nil, errors.New("set IRIS_BENCH_MYSQL_DSN to benchmark mysql")
+		}
+		st, err := store.NewMySQL(context.Background(), dsn)
+		if err != nil {
+			return nil, nil, err
+		}
+		return st, st.Close, nil
+	},
+	"postgres": func() (store.Store, func() error, error) {
+		dsn := os.Getenv("IRIS_BENCH_PG_DSN")
+		if dsn == "" {
+			return nil, nil, errors.New("set IRIS_BENCH_PG_DSN to benchmark postgres")
+		}
+		st, err := store.NewPostgres(context.Background(), dsn, 0)
+		if err != nil {
+			return nil, nil, err
+		}
+		return st, st.Close, nil
+	},
+}
+
+// runBenchStore implements "iris benchstore": a fixed CRUD workload
+// run against each selected backend, reported as a throughput and
+// latency table so operators can compare them on their own hardware.
+func runBenchStore(args []string) {
+	fs := flag.NewFlagSet("benchstore", flag.ExitOnError)
+	backends := fs.String("backends", "memory", "comma-separated backends to benchmark")
+	seed := fs.Int("seed-users", 1000, "users created before the measured phase")
+	ops := fs.Int("ops", 10000, "measured operations per backend (25% each CRUD)")
+	fs.Parse(args)
+
+	for _, name := range strings.Split(*backends, ",") {
+		name = strings.TrimSpace(name)
+		open, ok := benchBackends[name]
+		if !ok {
+			log.Fatalf("benchstore: unknown backend %q (have: %s)", name, strings.Join(benchBackendNames(), ", "))
+		}
+		st, cleanup, err := open()
+		if err != nil {
+			log.Fatalf("benchstore: opening %s: %v", name, err)
+		}
+		benchOne(name, st, *seed, *ops)
+		if err := cleanup(); err != nil {
+			log.Printf("benchstore: closing %s: %v", name, err)
+		}
+	}
+}
+
+func benchBackendNames() []string {
+	names := make([]string, 0, len(benchBackends))
+	for name := range benchBackends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func benchOne(name string, st store.Store, seed, ops int) {
+	ctx := context.Background()
+	rng := rand.New(rand.NewSource(1))
+
+	ids := make([]int, 0, seed)
+	for i := 0; i < seed; i++ {
+		u, err := st.Create(ctx, models.User{Name: fmt.Sprintf("seed-%d", i), Email: fmt.Sprintf("seed-%d@bench", i), Age: 20 + i%50})
+		if err != nil {
+			log.Fatalf("benchstore: seeding %s: %v", name, err)
+		}
+		ids = append(ids, u.ID)
+	}
+
+	lat := map[string][]time.Duration{}
+	start := time.Now()
+	for i := 0; i < ops; i++ {
+		id := ids[rng.Intn(len(ids))]
+		var (
+			op  string
+			err error
+		)
+		begin := time.Now()
+		switch i % 4 {
+		case 0:
+			op = "create"
+			var u models.User
+			u, err = st.Create(ctx, models.User{Name: fmt.Sprintf("bench-%d", i), Email: fmt.Sprintf("bench-%d@bench", i)})
+			if err == nil {
+				ids = append(ids, u.ID)
+			}
+		case 1:
+			op = "get"
+			_, err = st.Get(ctx, id)
+		case 2:
+			op = "update"
+			err = st.Update(ctx, models.User{ID: id, Name: "updated", Email: fmt.Sprintf("updated-%d@bench", i)})
+		case 3:
+			op = "delete"
+			err = st.Delete(ctx, id)
+		}
+		lat[op] = append(lat[op], time.Since(begin))
+		// Previously deleted IDs make some operations miss; that is
+		// part of the workload, not a failure.
+		if err != nil && !errors.Is(err, store.ErrNotFound) {
+			log.Fatalf("benchstore: %s %s: %v", name, op, err)
+		}
+	}
+	elapsed := time.Since(start)
+
+	fmt.Printf("\n== %s: %d ops in %v (%.0f ops/sec) ==\n", name, ops, elapsed.Round(time.Millisecond), float64(ops)/elapsed.Seconds())
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "op\tcount\tavg\tp50\tp95\tp99")
+	for _, op := range []string{"create", "get", "update", "delete"} {
+		ds := lat[op]
+		sort.Slice(ds, func(i, j int) bool { return ds[i] < ds[j] })
+		var sum time.Duration
+		for _, d := range ds {
+			sum += d
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%v\t%v\t%v\t%v\n", op, len(ds),
+			(sum / time.Duration(len(ds))).Round(time.Nanosecond*10),
+			percentile(ds, 0.50), percentile(ds, 0.95), percentile(ds, 0.99))
+	}
+	tw.Flush()
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(p * float64(len(sorted)-1))
+	return sorted[i].Round(10 * time.Nanosecond)
+}
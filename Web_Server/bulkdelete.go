@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+)
+
+// bulkDeleteOutcome is one row of a bulk delete response.
+type bulkDeleteOutcome struct {
+	ID     int    `json:"id"`
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// bulkDeleteUsers handles DELETE /users: the targets come from
+// ?ids=1,2,3 or, failing that, a JSON array body. Every ID is
+// attempted and reported individually; the response is 200 when all
+// succeeded and 207 otherwise, mirroring the import report.
+func bulkDeleteUsers(w http.ResponseWriter, r *http.Request) {
+	ids, ok := bulkDeleteIDs(w, r)
+	if !ok {
+		return
+	}
+	if len(ids) == 0 {
+		apiError(w, r, http.StatusBadRequest, "no ids given")
+		return
+	}
+
+	outcomes := make([]bulkDeleteOutcome, len(ids))
+	failed := 0
+	for i, id := range ids {
+		outcomes[i] = bulkDeleteOutcome{ID: id, Status: http.StatusNoContent}
+		u, err := users.Get(r.Context(), id)
+		if err == nil {
+			err = users.Delete(r.Context(), id)
+		}
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			outcomes[i].Status = http.StatusNotFound
+			outcomes[i].Detail = "user not found"
+			failed++
+		case err != nil:
+			outcomes[i].Status = http.StatusInternalServerError
+			outcomes[i].Detail = "internal error"
+			failed++
+		default:
+			recordAudit(r, "delete", id, nil)
+			emitUserEvent("user.deleted", u)
+		}
+	}
+
+	status := http.StatusOK
+	if failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	respond.JSON(w, status, outcomes)
+}
+
+// bulkDeleteIDs extracts the target IDs, answering the error response
+// itself when the input is malformed.
+func bulkDeleteIDs(w http.ResponseWriter, r *http.Request) ([]int, bool) {
+	if raw := r.URL.Query().Get("ids"); raw != "" {
+		var ids []int
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				apiError(w, r, http.StatusBadRequest, "invalid id "+strconv.Quote(part))
+				return nil, false
+			}
+			ids = append(ids, id)
+		}
+		return ids, true
+	}
+	var ids []int
+	if !decodeJSON(w, r, &ids) {
+		return nil, false
+	}
+	return ids, true
+}
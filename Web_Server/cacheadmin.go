@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/cache"
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+)
+
+// cachedUsers is non-nil when the repository is wrapped in the user
+// read cache; warm-up and the janitor reach it through this.
+var cachedUsers *store.Cached
+
+// newUserCache wraps st in the read cache when IRIS_USER_CACHE (max
+// entries) is set; IRIS_USER_CACHE_TTL bounds how stale a cached user
+// may get (default 30s), and IRIS_USER_CACHE_MODE picks how mutations
+// propagate: invalidate (default), write-through, or write-behind.
+// IRIS_USER_CACHE_LISTS caches that many whole list results, retired
+// wholesale by a generation bump on any mutation.
+// Not-found lookups are cached too, for IRIS_USER_CACHE_NEGATIVE
+// (default 5s, "off" disables). IRIS_USER_CACHE_STALE grants expired
+// entries a stale-while-revalidate grace window, and
+// IRIS_USER_CACHE_BLOOM=true fronts reads with a bloom filter so
+// lookups of never-written IDs and emails skip the backend. IRIS_USER_CACHE_REDIS points the
+// cross-instance invalidation channel at a Redis, for replicas that
+// share one backend, and IRIS_USER_CACHE_NODES (comma-separated
+// addresses) adds a consistent-hashed fleet of cache nodes as a
+// shared tier between the local cache and the store, with
+// IRIS_USER_CACHE_CODEC (json, gob, or msgpack) picking the value
+// encoding on that tier.
+func newUserCache(st store.Store) (store.Store, error) {
+	raw := os.Getenv("IRIS_USER_CACHE")
+	if raw == "" {
+		return st, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return nil, fmt.Errorf("invalid IRIS_USER_CACHE %q", raw)
+	}
+	ttl := 30 * time.Second
+	if v := os.Getenv("IRIS_USER_CACHE_TTL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid IRIS_USER_CACHE_TTL %q", v)
+		}
+		ttl = d
+	}
+	mode := store.WriteInvalidate
+	switch v := os.Getenv("IRIS_USER_CACHE_MODE"); v {
+	case "", "invalidate":
+	case "write-through":
+		mode = store.WriteThrough
+	case "write-behind":
+		mode = store.WriteBehind
+	default:
+		return nil, fmt.Errorf("invalid IRIS_USER_CACHE_MODE %q", v)
+	}
+	cachedUsers = store.NewCached(st, n, ttl, mode)
+	negative := 5 * time.Second
+	if v := os.Getenv("IRIS_USER_CACHE_NEGATIVE"); v != "" {
+		if v == "off" {
+			negative = 0
+		} else {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("invalid IRIS_USER_CACHE_NEGATIVE %q", v)
+			}
+			negative = d
+		}
+	}
+	if negative > 0 {
+		cachedUsers.SetNegativeTTL(negative)
+	}
+	if v := os.Getenv("IRIS_USER_CACHE_SLIDING"); v == "true" {
+		cachedUsers.SetSliding(true)
+	}
+	if v := os.Getenv("IRIS_USER_CACHE_STALE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid IRIS_USER_CACHE_STALE %q", v)
+		}
+		cachedUsers.SetStaleWindow(d)
+	}
+	if v := os.Getenv("IRIS_USER_CACHE_BLOOM"); v == "true" {
+		if err := cachedUsers.EnableBloom(context.Background()); err != nil {
+			return nil, fmt.Errorf("user cache bloom filter: %w", err)
+		}
+	}
+	if v := os.Getenv("IRIS_USER_CACHE_LISTS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid IRIS_USER_CACHE_LISTS %q", v)
+		}
+		cachedUsers.SetListCache(n)
+	}
+	if addr := os.Getenv("IRIS_USER_CACHE_REDIS"); addr != "" {
+		inv, err := store.NewInvalidator(context.Background(), addr)
+		if err != nil {
+			return nil, fmt.Errorf("user cache invalidator: %w", err)
+		}
+		cachedUsers.SetInvalidator(inv)
+	}
+	if nodes := os.Getenv("IRIS_USER_CACHE_NODES"); nodes != "" {
+		d, err := cache.NewDistributed(context.Background(), strings.Split(nodes, ","))
+		if err != nil {
+			return nil, fmt.Errorf("user cache nodes: %w", err)
+		}
+		cachedUsers.SetRemote(d)
+	}
+	if v := os.Getenv("IRIS_USER_CACHE_CODEC"); v != "" {
+		codec, ok := cache.CodecNamed(v)
+		if !ok {
+			return nil, fmt.Errorf("invalid IRIS_USER_CACHE_CODEC %q", v)
+		}
+		cachedUsers.SetCodec(codec)
+	}
+	return cachedUsers, nil
+}
+
+// warmUserCache preloads the IRIS_CACHE_WARM most recent users before
+// the listener opens, bounded by IRIS_CACHE_WARM_TIMEOUT (default 5s)
+// so a slow backend cannot hang startup. A warm-up that fails inside
+// its budget logs and moves on — a cold cache is degraded, not
+// broken.
+func warmUserCache() error {
+	raw := os.Getenv("IRIS_CACHE_WARM")
+	if raw == "" || cachedUsers == nil {
+		return nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return fmt.Errorf("invalid IRIS_CACHE_WARM %q", raw)
+	}
+	timeout := 5 * time.Second
+	if v := os.Getenv("IRIS_CACHE_WARM_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid IRIS_CACHE_WARM_TIMEOUT %q", v)
+		}
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	count, err := cachedUsers.Warm(ctx, n)
+	if err != nil {
+		log.Printf("iris: cache warm-up: %v", err)
+		return nil
+	}
+	log.Printf("iris: warmed user cache with %d users", count)
+	return nil
+}
+
+// startCacheJanitors begins the background expiry sweeps for the
+// process's cache instances, stopping when ctx does. IRIS_CACHE_SWEEP
+// sets the cadence (default 1m); "off" disables sweeping, leaving
+// expired entries to lazy removal on access.
+func startCacheJanitors(ctx context.Context) error {
+	every := time.Minute
+	if v := os.Getenv("IRIS_CACHE_SWEEP"); v != "" {
+		if v == "off" {
+			return nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid IRIS_CACHE_SWEEP %q", v)
+		}
+		every = d
+	}
+	oauthStates.StartJanitor(ctx, every)
+	if cachedUsers != nil {
+		cachedUsers.StartJanitor(ctx, every)
+	}
+	return nil
+}
+
+// adminCacheStats serves GET /admin/cache/stats: the hit, miss,
+// eviction, and size counters of every registered cache instance,
+// keyed by name. The same numbers feed the Prometheus rendering at
+// /admin/debug/metrics.
+func adminCacheStats(w http.ResponseWriter, r *http.Request) {
+	respond.JSON(w, http.StatusOK, cache.Snapshot())
+}
+
+// adminCache resolves the ?cache= parameter against the admin
+// registry, writing the error response itself when it cannot.
+func adminCache(w http.ResponseWriter, r *http.Request) (cache.Admin, bool) {
+	name := r.URL.Query().Get("cache")
+	if name == "" {
+		apiError(w, r, http.StatusBadRequest, "cache query parameter is required")
+		return cache.Admin{}, false
+	}
+	a, ok := cache.AdminOf(name)
+	if !ok {
+		apiError(w, r, http.StatusNotFound, "unknown cache")
+		return cache.Admin{}, false
+	}
+	return a, true
+}
+
+// adminCacheFlush serves POST /admin/cache/flush?cache=users: drop
+// every entry of the named cache. The blunt instrument for confirmed
+// stale data — the next reads repopulate from the backend.
+func adminCacheFlush(w http.ResponseWriter, r *http.Request) {
+	a, ok := adminCache(w, r)
+	if !ok {
+		return
+	}
+	a.Flush()
+	respond.NoContent(w)
+}
+
+// adminCacheKeys serves GET /admin/cache/keys?cache=users: the cached
+// keys, filtered by ?prefix= and paginated with the usual
+// limit/offset parameters (default 100), total in X-Total-Count.
+func adminCacheKeys(w http.ResponseWriter, r *http.Request) {
+	a, ok := adminCache(w, r)
+	if !ok {
+		return
+	}
+	limit, offset := 100, 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			apiError(w, r, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			apiError(w, r, http.StatusBadRequest, "invalid offset")
+			return
+		}
+		offset = n
+	}
+	keys := a.Keys()
+	if prefix := r.URL.Query().Get("prefix"); prefix != "" {
+		kept := keys[:0]
+		for _, k := range keys {
+			if strings.HasPrefix(k, prefix) {
+				kept = append(kept, k)
+			}
+		}
+		keys = kept
+	}
+	total := len(keys)
+	if offset > total {
+		offset = total
+	}
+	keys = keys[offset:]
+	if limit > 0 && len(keys) > limit {
+		keys = keys[:limit]
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	respond.JSON(w, http.StatusOK, keys)
+}
+
+// adminCacheEntry serves /admin/cache/entry?cache=users&key=42: GET
+// inspects the entry — value, remaining TTL, hit count — and DELETE
+// evicts it, the precise alternative to a flush.
+func adminCacheEntry(w http.ResponseWriter, r *http.Request) {
+	a, ok := adminCache(w, r)
+	if !ok {
+		return
+	}
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		apiError(w, r, http.StatusBadRequest, "key query parameter is required")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		info, ok := a.Inspect(key)
+		if !ok {
+			apiError(w, r, http.StatusNotFound, "key not cached")
+			return
+		}
+		respond.JSON(w, http.StatusOK, info)
+	case http.MethodDelete:
+		if !a.Delete(key) {
+			apiError(w, r, http.StatusNotFound, "key not cached")
+			return
+		}
+		respond.NoContent(w)
+	}
+}
+
+// adminCacheTTL serves GET /admin/cache/ttl?cache=users&key=42: how
+// long the named cache will keep that key. The key travels as a
+// string; each cache's registered lookup converts it. Useful when
+// deciding whether a stale read came from a cache entry that has not
+// run out yet.
+func adminCacheTTL(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("cache")
+	key := r.URL.Query().Get("key")
+	if name == "" || key == "" {
+		apiError(w, r, http.StatusBadRequest, "cache and key query parameters are required")
+		return
+	}
+	ttl, found, known := cache.LookupTTL(name, key)
+	switch {
+	case !known:
+		apiError(w, r, http.StatusNotFound, "unknown cache")
+	case !found:
+		apiError(w, r, http.StatusNotFound, "key not cached")
+	case ttl == 0:
+		respond.JSON(w, http.StatusOK, map[string]any{"cache": name, "key": key, "expires": false})
+	default:
+		respond.JSON(w, http.StatusOK, map[string]any{"cache": name, "key": key, "expires": true, "ttl": ttl.String()})
+	}
+}
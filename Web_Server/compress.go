@@ -0,0 +1,217 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// compressMinSize is the smallest body worth compressing; responses
+// below it ship as-is to avoid paying header overhead for nothing.
+const compressMinSize = 1 << 10
+
+var (
+	gzipPool = sync.Pool{New: func() any {
+		return gzip.NewWriter(io.Discard)
+	}}
+	brotliPool = sync.Pool{New: func() any {
+		return brotli.NewWriter(io.Discard)
+	}}
+)
+
+// withCompression negotiates response compression from Accept-Encoding,
+// preferring brotli over gzip. Bodies are buffered up to compressMinSize
+// before committing so small responses and already-compressed content
+// types pass through untouched.
+func withCompression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		enc := negotiateEncoding(r)
+		if enc == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+		cw := &compressWriter{ResponseWriter: w, encoding: enc, status: http.StatusOK}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks the strongest encoding the client accepts,
+// or "" for identity.
+func negotiateEncoding(r *http.Request) string {
+	accept := r.Header.Get("Accept-Encoding")
+	var br, gz bool
+	for _, part := range strings.Split(accept, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if qvalue(params) == 0 {
+			continue
+		}
+		switch strings.TrimSpace(name) {
+		case "br":
+			br = true
+		case "gzip":
+			gz = true
+		}
+	}
+	if br {
+		return "br"
+	}
+	if gz {
+		return "gzip"
+	}
+	return ""
+}
+
+// qvalue extracts the q parameter from an Accept-Encoding member's
+// parameters. Absent or malformed q means fully acceptable (1); any
+// spelling of zero — "0", "0.0", "0.00" — means not acceptable.
+func qvalue(params string) float64 {
+	for _, p := range strings.Split(params, ";") {
+		k, v, ok := strings.Cut(strings.TrimSpace(p), "=")
+		if !ok || strings.TrimSpace(k) != "q" {
+			continue
+		}
+		q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 1
+		}
+		return q
+	}
+	return 1
+}
+
+// incompressible reports whether a content type is already compressed
+// and not worth re-encoding.
+func incompressible(ct string) bool {
+	ct, _, _ = strings.Cut(ct, ";")
+	ct = strings.TrimSpace(ct)
+	if ct == "image/svg+xml" {
+		return false
+	}
+	for _, prefix := range []string{"image/", "video/", "audio/"} {
+		if strings.HasPrefix(ct, prefix) {
+			return true
+		}
+	}
+	switch ct {
+	case "application/zip", "application/gzip", "application/zstd",
+		"application/octet-stream":
+		return true
+	}
+	return false
+}
+
+// compressWriter defers the compress-or-not decision until either
+// compressMinSize bytes have been written or the handler returns, so
+// headers stay mutable until the body's size and type are known.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	status   int
+	buf      []byte
+	enc      io.WriteCloser // nil until committed to compressing
+	decided  bool
+}
+
+func (cw *compressWriter) WriteHeader(status int) {
+	if !cw.decided {
+		cw.status = status
+		return
+	}
+	cw.ResponseWriter.WriteHeader(status)
+}
+
+func (cw *compressWriter) Write(p []byte) (int, error) {
+	if cw.decided {
+		if cw.enc != nil {
+			return cw.enc.Write(p)
+		}
+		return cw.ResponseWriter.Write(p)
+	}
+	cw.buf = append(cw.buf, p...)
+	if len(cw.buf) >= compressMinSize {
+		if err := cw.decide(false); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush commits the current choice and forwards the flush, so
+// streaming handlers (export, SSE) keep working under the middleware.
+// An early flush signals a stream, so size no longer gates compression.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.decide(true)
+	}
+	if cw.enc != nil {
+		if f, ok := cw.enc.(interface{ Flush() error }); ok {
+			f.Flush()
+		}
+	}
+	if f, ok := cw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// decide commits: start a compressor if the buffered body warrants it
+// (or a stream is expected), otherwise fall through to the plain
+// writer. Either way the status and buffered bytes move downstream.
+func (cw *compressWriter) decide(streaming bool) error {
+	cw.decided = true
+	compress := (streaming || len(cw.buf) >= compressMinSize) &&
+		cw.Header().Get("Content-Encoding") == "" &&
+		!incompressible(cw.contentType())
+	if compress {
+		cw.Header().Set("Content-Encoding", cw.encoding)
+		cw.Header().Del("Content-Length")
+		switch cw.encoding {
+		case "br":
+			enc := brotliPool.Get().(*brotli.Writer)
+			enc.Reset(cw.ResponseWriter)
+			cw.enc = enc
+		default:
+			enc := gzipPool.Get().(*gzip.Writer)
+			enc.Reset(cw.ResponseWriter)
+			cw.enc = enc
+		}
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(cw.buf)
+	} else if len(cw.buf) > 0 {
+		_, err = cw.ResponseWriter.Write(cw.buf)
+	}
+	cw.buf = nil
+	return err
+}
+
+func (cw *compressWriter) contentType() string {
+	if ct := cw.Header().Get("Content-Type"); ct != "" {
+		return ct
+	}
+	return http.DetectContentType(cw.buf)
+}
+
+// close flushes whatever is pending and returns the compressor to its
+// pool. Called once the handler returns.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		cw.decide(false)
+	}
+	switch enc := cw.enc.(type) {
+	case *gzip.Writer:
+		enc.Close()
+		gzipPool.Put(enc)
+	case *brotli.Writer:
+		enc.Close()
+		brotliPool.Put(enc)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/pool"
+)
+
+// runConcurrencyDemo exercises the worker pool package: three workers
+// doubling ten numbers, results printed as they complete. Kept from
+// early prototyping as a smoke test for the pool's lifecycle.
+func runConcurrencyDemo() {
+	p := pool.New(context.Background(), 3, func(_ context.Context, j int) int {
+		time.Sleep(100 * time.Millisecond)
+		return j * 2
+	})
+
+	go func() {
+		for j := 1; j <= 10; j++ {
+			p.Submit(j)
+		}
+		p.Stop()
+	}()
+
+	for r := range p.Results() {
+		fmt.Println("result:", r)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/divijg19/Iris/Web_Server/internal/config"
+)
+
+// runConfigCmd implements the `config` subcommand. `config dump`
+// prints the effective configuration after all sources are merged —
+// the quickest way to see what a deployment actually resolved to —
+// with credentials masked so the output is safe to paste into a bug
+// report.
+func runConfigCmd(args []string) {
+	fs := flag.NewFlagSet("config", flag.ExitOnError)
+	path := fs.String("config", os.Getenv("IRIS_CONFIG"), "config file ('key: value' lines)")
+	fs.Parse(args)
+
+	if fs.Arg(0) != "dump" {
+		fmt.Fprintln(os.Stderr, "usage: iris config [-config file] dump")
+		os.Exit(2)
+	}
+	cfg, err := config.Load(*path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	cfg.Dump(os.Stdout)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	httppprof "net/http/pprof"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+
+	"github.com/divijg19/Iris/Web_Server/internal/cache"
+)
+
+// debugRoutes serves the runtime introspection endpoints under
+// /admin/debug/: the pprof profiles, expvar counters, and a full
+// goroutine dump. They sit behind the admin scope like the rest of
+// /admin/, and are registered without a route timeout so long-running
+// CPU profiles (?seconds=30) and traces can finish.
+//
+//	/admin/debug/pprof/      profile index, heap, goroutine, ...
+//	/admin/debug/pprof/profile  CPU profile
+//	/admin/debug/pprof/trace    execution trace
+//	/admin/debug/vars        expvar (memstats, cmdline)
+//	/admin/debug/metrics     the numeric counters in Prometheus text format
+//	/admin/debug/goroutines  plain-text stacks of every goroutine
+func debugRoutes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/admin/debug/")
+	switch {
+	case name == "vars":
+		expvar.Handler().ServeHTTP(w, r)
+	case name == "metrics":
+		serveMetrics(w, r)
+	case name == "goroutines":
+		dumpGoroutines(w)
+	case strings.HasPrefix(name, "pprof"):
+		servePprof(w, r, strings.TrimPrefix(strings.TrimPrefix(name, "pprof"), "/"))
+	default:
+		apiError(w, r, http.StatusNotFound, "unknown debug endpoint")
+	}
+}
+
+// servePprof dispatches to the net/http/pprof handlers. The index
+// handler keys off the canonical /debug/pprof/ path, so the request
+// is re-rooted before delegating; its links are relative and keep
+// working under the admin prefix.
+func servePprof(w http.ResponseWriter, r *http.Request, profile string) {
+	switch profile {
+	case "":
+		r2 := new(http.Request)
+		*r2 = *r
+		u := *r.URL
+		u.Path = "/debug/pprof/"
+		r2.URL = &u
+		httppprof.Index(w, r2)
+	case "cmdline":
+		httppprof.Cmdline(w, r)
+	case "profile":
+		httppprof.Profile(w, r)
+	case "symbol":
+		httppprof.Symbol(w, r)
+	case "trace":
+		httppprof.Trace(w, r)
+	default:
+		httppprof.Handler(profile).ServeHTTP(w, r)
+	}
+}
+
+// dumpGoroutines writes every goroutine's stack in the expanded text
+// form, the first thing to grab from an instance that looks wedged.
+func dumpGoroutines(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Goroutine-Count", strconv.Itoa(runtime.NumGoroutine()))
+	pprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// serveMetrics renders the numeric expvar values plus the cache
+// registry in Prometheus text exposition format, for scrape-based
+// monitoring. Structured vars (memstats, cmdline) stay on
+// /admin/debug/vars.
+func serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	expvar.Do(func(kv expvar.KeyValue) {
+		switch v := kv.Value.(type) {
+		case *expvar.Int, *expvar.Float:
+			fmt.Fprintf(w, "%s %s\n", promName(kv.Key), v.(expvar.Var).String())
+		case expvar.Func:
+			if f, ok := promValue(v.Value()); ok {
+				fmt.Fprintf(w, "%s %g\n", promName(kv.Key), f)
+			}
+		}
+	})
+	snap := cache.Snapshot()
+	for _, name := range cache.Names() {
+		st := snap[name]
+		fmt.Fprintf(w, "iris_cache_hits_total{cache=%q} %d\n", name, st.Hits)
+		fmt.Fprintf(w, "iris_cache_misses_total{cache=%q} %d\n", name, st.Misses)
+		fmt.Fprintf(w, "iris_cache_evictions_total{cache=%q} %d\n", name, st.Evictions)
+		fmt.Fprintf(w, "iris_cache_expirations_total{cache=%q} %d\n", name, st.Expirations)
+		fmt.Fprintf(w, "iris_cache_entries{cache=%q} %d\n", name, st.Entries)
+		fmt.Fprintf(w, "iris_cache_estimated_bytes{cache=%q} %d\n", name, st.EstimatedBytes)
+		if total := st.Hits + st.Misses; total > 0 {
+			fmt.Fprintf(w, "iris_cache_hit_ratio{cache=%q} %g\n", name, float64(st.Hits)/float64(total))
+		}
+	}
+
+	// Shard-labeled series expose the skew the aggregates hide: one
+	// hot shard with the rest idle means a concentrated key space.
+	shards := cache.ShardSnapshot()
+	for _, name := range cache.Names() {
+		for i, st := range shards[name] {
+			fmt.Fprintf(w, "iris_cache_shard_hits_total{cache=%q,shard=\"%d\"} %d\n", name, i, st.Hits)
+			fmt.Fprintf(w, "iris_cache_shard_misses_total{cache=%q,shard=\"%d\"} %d\n", name, i, st.Misses)
+			fmt.Fprintf(w, "iris_cache_shard_evictions_total{cache=%q,shard=\"%d\"} %d\n", name, i, st.Evictions)
+			fmt.Fprintf(w, "iris_cache_shard_entries{cache=%q,shard=\"%d\"} %d\n", name, i, st.Entries)
+		}
+	}
+
+	// Loader histograms, in the cumulative bucket form Prometheus
+	// expects. A fat tail here with a healthy hit ratio points at the
+	// backend, not the cache.
+	loads := cache.LoadSnapshot()
+	for _, name := range cache.Names() {
+		h, ok := loads[name]
+		if !ok || h.Count == 0 {
+			continue
+		}
+		var cum uint64
+		for i, bound := range h.Bounds {
+			cum += h.Counts[i]
+			fmt.Fprintf(w, "iris_cache_load_duration_seconds_bucket{cache=%q,le=\"%g\"} %d\n", name, bound, cum)
+		}
+		fmt.Fprintf(w, "iris_cache_load_duration_seconds_bucket{cache=%q,le=\"+Inf\"} %d\n", name, h.Count)
+		fmt.Fprintf(w, "iris_cache_load_duration_seconds_sum{cache=%q} %g\n", name, h.Sum)
+		fmt.Fprintf(w, "iris_cache_load_duration_seconds_count{cache=%q} %d\n", name, h.Count)
+	}
+}
+
+// promName maps an expvar name onto the Prometheus charset.
+func promName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// promValue narrows an expvar.Func result to a number when it is one.
+func promValue(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
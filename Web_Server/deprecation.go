@@ -0,0 +1,29 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// deprecated marks a handler as scheduled for removal: responses gain
+// a Deprecation header (RFC 9745) and, when a retirement date is set,
+// a Sunset header (RFC 8594). The structured warning is logged once
+// per route per process rather than per call, so a chatty legacy
+// client cannot flood the log.
+func deprecated(name string, since, sunset time.Time, h http.Handler) http.Handler {
+	var once sync.Once
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "@"+strconv.FormatInt(since.Unix(), 10))
+		if !sunset.IsZero() {
+			w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+		}
+		once.Do(func() {
+			log.Printf("iris: level=warn msg=\"deprecated route called\" route=%q method=%s path=%s sunset=%s",
+				name, r.Method, r.URL.Path, sunset.UTC().Format(time.RFC3339))
+		})
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/divijg19/Iris/Web_Server/internal/i18n"
+	"github.com/divijg19/Iris/Web_Server/internal/problem"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+)
+
+// apiError is how every handler reports failure: an RFC 7807
+// application/problem+json document with the request path as the
+// instance. Keeping a single choke point means clients can parse any
+// iris error the same way — and localization happens here, so
+// handlers keep writing canonical English details.
+func apiError(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	locale := i18n.Match(r.Header.Get("Accept-Language"))
+	w.Header().Set("Content-Language", locale)
+	problem.Error(w, r, status, i18n.T(locale, detail))
+}
+
+// apiStoreError maps a repository failure onto the right status: a
+// blown route deadline becomes 504, a uniqueness violation 409, a
+// vanished client gets nothing, and everything else stays the opaque
+// 500.
+func apiStoreError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		apiError(w, r, http.StatusGatewayTimeout, "request deadline exceeded")
+	case errors.Is(err, store.ErrConflict):
+		apiError(w, r, http.StatusConflict, "email already in use")
+	case errors.Is(err, context.Canceled):
+		// The client hung up; there is no one left to answer.
+	default:
+		apiError(w, r, http.StatusInternalServerError, "internal error")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// userETag derives the weak validator for a user resource. The model
+// carries no version column, so the tag hashes the canonical scrubbed
+// representation — any observable change rolls it.
+func userETag(u models.User) string {
+	raw, _ := json.Marshal(scrubUser(u))
+	sum := sha256.Sum256(raw)
+	return fmt.Sprintf(`W/"%x"`, sum[:8])
+}
+
+// etagMatch applies the weak comparison to an If-Match or
+// If-None-Match header value; "*" matches any current tag.
+func etagMatch(header, tag string) bool {
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPrecondition enforces If-Match on writes when the client sent
+// one, answering 412 and reporting false if the resource moved on.
+// Clients that omit the header keep last-write-wins semantics.
+func checkPrecondition(w http.ResponseWriter, r *http.Request, u models.User) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" || etagMatch(header, userETag(u)) {
+		return true
+	}
+	apiError(w, r, http.StatusPreconditionFailed, "resource changed since the supplied If-Match")
+	return false
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"expvar"
+	"net/http"
+	"sync"
+)
+
+// notFoundTotal and methodNotAllowedTotal count the fallback
+// responses, published through expvar so /admin/debug/vars surfaces
+// how much traffic is probing paths and methods the API never had.
+var (
+	notFoundTotal         = expvar.NewInt("http_not_found_total")
+	methodNotAllowedTotal = expvar.NewInt("http_method_not_allowed_total")
+)
+
+// fallbacks holds the handlers for unmatched paths and wrong-method
+// requests. The defaults answer with the same problem+json shape as
+// every other error instead of ServeMux's plain-text page;
+// setNotFoundHandler and setMethodNotAllowedHandler swap in custom
+// ones without losing the counting.
+var fallbacks = struct {
+	mu               sync.RWMutex
+	notFound         http.HandlerFunc
+	methodNotAllowed http.HandlerFunc
+}{
+	notFound: func(w http.ResponseWriter, r *http.Request) {
+		apiError(w, r, http.StatusNotFound, "not found")
+	},
+	methodNotAllowed: func(w http.ResponseWriter, r *http.Request) {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	},
+}
+
+func setNotFoundHandler(h http.HandlerFunc) {
+	fallbacks.mu.Lock()
+	fallbacks.notFound = h
+	fallbacks.mu.Unlock()
+}
+
+func setMethodNotAllowedHandler(h http.HandlerFunc) {
+	fallbacks.mu.Lock()
+	fallbacks.methodNotAllowed = h
+	fallbacks.mu.Unlock()
+}
+
+// respondNotFound counts the miss and dispatches whichever handler is
+// registered.
+func respondNotFound(w http.ResponseWriter, r *http.Request) {
+	notFoundTotal.Add(1)
+	fallbacks.mu.RLock()
+	h := fallbacks.notFound
+	fallbacks.mu.RUnlock()
+	h(w, r)
+}
+
+// respondMethodNotAllowed does the same for 405s; callers set the
+// Allow header first, since only they know the route's methods.
+func respondMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	methodNotAllowedTotal.Add(1)
+	fallbacks.mu.RLock()
+	h := fallbacks.methodNotAllowed
+	fallbacks.mu.RUnlock()
+	h(w, r)
+}
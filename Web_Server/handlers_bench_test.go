@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// newBenchMux points the package-level repository at a fresh memory
+// store seeded with n users and builds the /v1 route table around it.
+// Auth stays disabled (no sessions, no authenticator), so these
+// numbers are router dispatch plus handler work — the outer logging,
+// compression, and rate-limit stack is deliberately not in the loop.
+func newBenchMux(b *testing.B, n int) *http.ServeMux {
+	b.Helper()
+	users = store.NewMemory()
+	ctx := context.Background()
+	for i := 0; i < n; i++ {
+		_, err := users.Create(ctx, models.User{
+			Name:  fmt.Sprintf("user-%d", i),
+			Email: fmt.Sprintf("user-%d@example.com", i),
+			Age:   20 + i%50,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+	return apiV1()
+}
+
+// BenchmarkHandlerGetUser measures GET /users/{id} end to end:
+// routing, the store read, ETag and Last-Modified computation, and
+// JSON encoding of the response.
+func BenchmarkHandlerGetUser(b *testing.B) {
+	const n = 1 << 10
+	mux := newBenchMux(b, n)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		id := 1
+		for pb.Next() {
+			req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/users/%d", id), nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				b.Fatalf("GET /users/%d: %d", id, rec.Code)
+			}
+			if id++; id > n {
+				id = 1
+			}
+		}
+	})
+}
+
+// BenchmarkHandlerListUsers measures a default collection page: the
+// full list-sort-paginate pass over the store plus link headers and
+// encoding of fifty users.
+func BenchmarkHandlerListUsers(b *testing.B) {
+	mux := newBenchMux(b, 1<<10)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			req := httptest.NewRequest(http.MethodGet, "/users?limit=50", nil)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				b.Fatalf("GET /users: %d", rec.Code)
+			}
+		}
+	})
+}
+
+// BenchmarkHandlerCreateUser measures the write path — decode,
+// sanitize, store insert, audit entry — without a password, so bcrypt
+// cost does not drown everything else out.
+func BenchmarkHandlerCreateUser(b *testing.B) {
+	mux := newBenchMux(b, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		body := fmt.Sprintf(`{"name":"bench-%d","email":"bench-%d@example.com","age":30}`, i, i)
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated {
+			b.Fatalf("POST /users: %d %s", rec.Code, rec.Body)
+		}
+	}
+}
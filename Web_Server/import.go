@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/divijg19/Iris/Web_Server/internal/redact"
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// importBatchSize caps how many rows go into one transaction so a bad
+// row late in a huge file doesn't roll back everything before it.
+const importBatchSize = 100
+
+// importRow is one line of the import report. Exactly one of ID or
+// Error is meaningful: ID for inserted rows, Error for rejected ones.
+type importRow struct {
+	Line  int    `json:"line"`
+	ID    int    `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// importReport summarizes a bulk import for the caller.
+type importReport struct {
+	Imported int         `json:"imported"`
+	Failed   int         `json:"failed"`
+	Rows     []importRow `json:"rows"`
+}
+
+// importUsers handles POST /users/import. The body is either NDJSON
+// (one user object per line) or CSV with a name,email,age header,
+// selected by Content-Type. Rows are validated individually and
+// inserted in batches; the response reports the outcome per row.
+func importUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var (
+		rows []importRow
+		err  error
+	)
+	ct := r.Header.Get("Content-Type")
+	switch {
+	case strings.HasPrefix(ct, "text/csv"):
+		rows, err = importCSV(r)
+	default:
+		rows, err = importNDJSON(r)
+	}
+	if err != nil {
+		if !writeBodyTooLarge(w, err) {
+			apiError(w, r, http.StatusBadRequest, redact.Error(err))
+		}
+		return
+	}
+
+	report := importReport{Rows: rows}
+	for _, row := range rows {
+		if row.Error == "" {
+			report.Imported++
+		} else {
+			report.Failed++
+		}
+	}
+
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	respond.JSON(w, status, report)
+}
+
+// importNDJSON reads one JSON user object per line.
+func importNDJSON(r *http.Request) ([]importRow, error) {
+	var (
+		rows  []importRow
+		batch []importCandidate
+	)
+	sc := bufio.NewScanner(r.Body)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		var u models.User
+		if err := json.Unmarshal([]byte(text), &u); err != nil {
+			rows = append(rows, importRow{Line: line, Error: "invalid JSON: " + err.Error()})
+			continue
+		}
+		batch, rows = stageImport(batch, rows, line, u)
+		if len(batch) >= importBatchSize {
+			rows = flushImport(r, batch, rows)
+			batch = batch[:0]
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+	return flushImport(r, batch, rows), nil
+}
+
+// importCSV reads rows of name,email,age under a header line naming
+// those columns in any order.
+func importCSV(r *http.Request) ([]importRow, error) {
+	cr := csv.NewReader(r.Body)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, errors.New("missing CSV header")
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		col[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"name", "email"} {
+		if _, ok := col[required]; !ok {
+			return nil, fmt.Errorf("CSV header missing %q column", required)
+		}
+	}
+
+	var (
+		rows  []importRow
+		batch []importCandidate
+	)
+	line := 1
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			rows = append(rows, importRow{Line: line, Error: err.Error()})
+			continue
+		}
+		u := models.User{Name: record[col["name"]], Email: record[col["email"]]}
+		if i, ok := col["age"]; ok && record[i] != "" {
+			age, err := strconv.Atoi(record[i])
+			if err != nil {
+				rows = append(rows, importRow{Line: line, Error: "invalid age"})
+				continue
+			}
+			u.Age = age
+		}
+		batch, rows = stageImport(batch, rows, line, u)
+		if len(batch) >= importBatchSize {
+			rows = flushImport(r, batch, rows)
+			batch = batch[:0]
+		}
+	}
+	return flushImport(r, batch, rows), nil
+}
+
+// importCandidate is a validated row waiting for its batch insert.
+type importCandidate struct {
+	line int
+	user models.User
+}
+
+// stageImport validates one decoded row, either queueing it for the
+// next batch or recording the rejection immediately.
+func stageImport(batch []importCandidate, rows []importRow, line int, u models.User) ([]importCandidate, []importRow) {
+	u = sanitizeUser(u)
+	if err := validateImport(u); err != nil {
+		return batch, append(rows, importRow{Line: line, Error: err.Error()})
+	}
+	return append(batch, importCandidate{line: line, user: u}), rows
+}
+
+func validateImport(u models.User) error {
+	if strings.TrimSpace(u.Name) == "" {
+		return errors.New("name is required")
+	}
+	if strings.TrimSpace(u.Email) == "" {
+		return errors.New("email is required")
+	}
+	if u.Age < 0 {
+		return errors.New("age must not be negative")
+	}
+	return nil
+}
+
+// flushImport inserts the staged batch in one transaction. A failed
+// insert is reported on its own row without aborting the rest of the
+// batch; IDs assigned by the store are echoed back per row.
+func flushImport(r *http.Request, batch []importCandidate, rows []importRow) []importRow {
+	if len(batch) == 0 {
+		return rows
+	}
+	var inserted []importRow
+	err := users.WithTx(r.Context(), func(tx store.Store) error {
+		for _, cand := range batch {
+			created, err := tx.Create(r.Context(), cand.user)
+			if err != nil {
+				inserted = append(inserted, importRow{Line: cand.line, Error: err.Error()})
+				continue
+			}
+			recordAudit(r, "create", created.ID, nil)
+			emitUserEvent("user.created", created)
+			inserted = append(inserted, importRow{Line: cand.line, ID: created.ID})
+		}
+		return nil
+	})
+	if err != nil {
+		// The transaction failed to commit: none of the batch landed.
+		for _, cand := range batch {
+			rows = append(rows, importRow{Line: cand.line, Error: err.Error()})
+		}
+		return rows
+	}
+	return append(rows, inserted...)
+}
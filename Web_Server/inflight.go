@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRouteInflight caps concurrent requests on the bulk routes,
+// whose handlers hold goroutines (and store transactions) far longer
+// than the JSON CRUD paths.
+var defaultRouteInflight = map[string]int{
+	"/batch":        8,
+	"/users/export": 2,
+	"/users/import": 2,
+}
+
+// inflight holds the live gates: one process-wide, plus one per route
+// pattern. Config reloads rebuild and swap them wholesale; a request
+// that already holds a slot releases into the gate it took it from,
+// so replaced gates simply drain.
+var inflight = struct {
+	mu     sync.RWMutex
+	global *gate
+	routes map[string]*gate
+	wait   time.Duration
+}{wait: 100 * time.Millisecond}
+
+// applyInflightLimits rebuilds the gates from the defaults plus
+// IRIS_MAX_INFLIGHT (process-wide cap, unset leaves it off),
+// IRIS_ROUTE_INFLIGHT ("pattern=n,pattern=n"), and IRIS_INFLIGHT_WAIT
+// (how long a request may queue for a slot before being turned away,
+// default 100ms). Safe to call again on reload.
+func applyInflightLimits() error {
+	var global *gate
+	if v := os.Getenv("IRIS_MAX_INFLIGHT"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid IRIS_MAX_INFLIGHT %q", v)
+		}
+		global = newGate(n)
+	}
+	caps := make(map[string]int, len(defaultRouteInflight))
+	for pattern, n := range defaultRouteInflight {
+		caps[pattern] = n
+	}
+	if raw := os.Getenv("IRIS_ROUTE_INFLIGHT"); raw != "" {
+		for _, pair := range strings.Split(raw, ",") {
+			pattern, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return fmt.Errorf("invalid IRIS_ROUTE_INFLIGHT entry %q", pair)
+			}
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return fmt.Errorf("invalid IRIS_ROUTE_INFLIGHT cap %q", val)
+			}
+			caps[pattern] = n
+		}
+	}
+	routes := make(map[string]*gate, len(caps))
+	for pattern, n := range caps {
+		routes[pattern] = newGate(n)
+	}
+	wait := 100 * time.Millisecond
+	if v := os.Getenv("IRIS_INFLIGHT_WAIT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid IRIS_INFLIGHT_WAIT %q", v)
+		}
+		wait = d
+	}
+	inflight.mu.Lock()
+	inflight.global = global
+	inflight.routes = routes
+	inflight.wait = wait
+	inflight.mu.Unlock()
+	return nil
+}
+
+// withInflightLimit wraps the whole mux with the process-wide cap.
+// With IRIS_MAX_INFLIGHT unset every request passes straight through;
+// past the cap a request queues briefly for a freed slot and is then
+// turned away with a 503, which keeps a burst from exhausting handler
+// goroutines.
+func withInflightLimit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inflight.mu.RLock()
+		g, wait := inflight.global, inflight.wait
+		inflight.mu.RUnlock()
+		if g == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !g.acquire(r.Context(), wait) {
+			w.Header().Set("Retry-After", "1")
+			apiError(w, r, http.StatusServiceUnavailable, "server at capacity")
+			return
+		}
+		defer g.release()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// guarded wraps h with the concurrency cap registered for pattern.
+// The gate is looked up per request so a reload takes effect
+// immediately; a route past its own cap answers 429 rather than the
+// global 503, since the rest of the API is still healthy.
+func guarded(pattern string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		inflight.mu.RLock()
+		g, wait := inflight.routes[pattern], inflight.wait
+		inflight.mu.RUnlock()
+		if g == nil {
+			h(w, r)
+			return
+		}
+		if !g.acquire(r.Context(), wait) {
+			w.Header().Set("Retry-After", "1")
+			apiError(w, r, http.StatusTooManyRequests, "too many concurrent requests")
+			return
+		}
+		defer g.release()
+		h(w, r)
+	}
+}
+
+// gate is a counting semaphore over a buffered channel.
+type gate struct {
+	slots chan struct{}
+}
+
+func newGate(n int) *gate {
+	return &gate{slots: make(chan struct{}, n)}
+}
+
+// acquire takes a slot, queueing for up to wait, and reports whether
+// it got one. A caller that goes away while queued stops waiting.
+func (g *gate) acquire(ctx context.Context, wait time.Duration) bool {
+	select {
+	case g.slots <- struct{}{}:
+		return true
+	default:
+	}
+	if wait <= 0 {
+		return false
+	}
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case g.slots <- struct{}{}:
+		return true
+	case <-t.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (g *gate) release() {
+	<-g.slots
+}
@@ -0,0 +1,96 @@
+// Package audit records who changed what and when. Entries go to an
+// in-memory ring buffer sized for recent history; operators needing
+// long-term retention can ship the admin endpoint's output elsewhere.
+package audit
+
+import (
+	"sync"
+	"time"
+)
+
+// Change is one field's before/after pair in a diff.
+type Change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Entry is one recorded mutation.
+type Entry struct {
+	ID   int64     `json:"id"`
+	Time time.Time `json:"time"`
+
+	// Actor identifies who made the change: the authenticated email,
+	// or "anonymous" when auth is disabled.
+	Actor string `json:"actor"`
+
+	// Action is "create", "update", "delete", or "restore".
+	Action string `json:"action"`
+
+	// TargetID is the affected user's ID.
+	TargetID int `json:"target_id"`
+
+	// Diff maps changed field names to their before/after values.
+	Diff map[string]Change `json:"diff,omitempty"`
+
+	// RequestID ties the entry to the HTTP request that caused it.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Filter narrows a List call. Zero values match everything.
+type Filter struct {
+	Actor string
+	Since time.Time
+	Until time.Time
+}
+
+// Log is a bounded, concurrency-safe audit trail. Once full, the
+// oldest entries are dropped.
+type Log struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int64
+	max     int
+}
+
+// NewLog returns a Log keeping at most max entries (default 10000).
+func NewLog(max int) *Log {
+	if max <= 0 {
+		max = 10000
+	}
+	return &Log{max: max}
+}
+
+// Record appends an entry, stamping its ID and time.
+func (l *Log) Record(e Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.next++
+	e.ID = l.next
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+	l.entries = append(l.entries, e)
+	if len(l.entries) > l.max {
+		l.entries = l.entries[len(l.entries)-l.max:]
+	}
+}
+
+// List returns entries matching f, oldest first.
+func (l *Log) List(f Filter) []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var out []Entry
+	for _, e := range l.entries {
+		if f.Actor != "" && e.Actor != f.Actor {
+			continue
+		}
+		if !f.Since.IsZero() && e.Time.Before(f.Since) {
+			continue
+		}
+		if !f.Until.IsZero() && e.Time.After(f.Until) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
@@ -0,0 +1,200 @@
+// Package auth issues and verifies the credentials protecting the
+// user API: signed JWT access tokens plus the HTTP middleware that
+// checks them and exposes the caller's identity on the request
+// context.
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidToken is returned for tokens that are missing, malformed,
+// expired, or signed with the wrong key.
+var ErrInvalidToken = errors.New("auth: invalid token")
+
+// Error renders the middleware's 401/403 responses. It defaults to
+// plain http.Error so the package stands alone; the server swaps in
+// its problem+json renderer at startup so auth failures look like
+// every other API error.
+var Error = func(w http.ResponseWriter, _ *http.Request, status int, detail string) {
+	http.Error(w, detail, status)
+}
+
+// Config selects the signing algorithm and keys for access tokens.
+type Config struct {
+	// Alg is "HS256" or "RS256".
+	Alg string
+
+	// Secret is the HMAC key for HS256.
+	Secret []byte
+
+	// PrivateKey/PublicKey are the RS256 key pair. Verification only
+	// needs the public half.
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+
+	// AccessTTL is how long issued access tokens live; it defaults to
+	// fifteen minutes.
+	AccessTTL time.Duration
+}
+
+// Authenticator issues and verifies access tokens per its Config.
+type Authenticator struct {
+	cfg Config
+}
+
+// New validates cfg and returns an Authenticator.
+func New(cfg Config) (*Authenticator, error) {
+	if cfg.AccessTTL <= 0 {
+		cfg.AccessTTL = 15 * time.Minute
+	}
+	switch cfg.Alg {
+	case "HS256":
+		if len(cfg.Secret) == 0 {
+			return nil, errors.New("auth: HS256 requires a secret")
+		}
+	case "RS256":
+		if cfg.PrivateKey == nil || cfg.PublicKey == nil {
+			return nil, errors.New("auth: RS256 requires a key pair")
+		}
+	default:
+		return nil, fmt.Errorf("auth: unsupported algorithm %q", cfg.Alg)
+	}
+	return &Authenticator{cfg: cfg}, nil
+}
+
+// claims is the JWT payload: standard registered claims with the user
+// ID as subject, plus the email and granted scopes. Scope follows the
+// OAuth convention of a space-separated list.
+type claims struct {
+	Email string `json:"email,omitempty"`
+	Scope string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// Issue signs an access token for the given user carrying the given
+// scopes.
+func (a *Authenticator) Issue(userID int, email string, scopes ...string) (string, error) {
+	now := time.Now()
+	c := claims{
+		Email: email,
+		Scope: strings.Join(scopes, " "),
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   fmt.Sprint(userID),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.cfg.AccessTTL)),
+			Issuer:    "iris",
+		},
+	}
+	switch a.cfg.Alg {
+	case "HS256":
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, c).SignedString(a.cfg.Secret)
+	default:
+		return jwt.NewWithClaims(jwt.SigningMethodRS256, c).SignedString(a.cfg.PrivateKey)
+	}
+}
+
+// AccessTTL reports how long issued tokens live.
+func (a *Authenticator) AccessTTL() time.Duration { return a.cfg.AccessTTL }
+
+// Verify parses and validates a token, returning the identity it was
+// issued for.
+func (a *Authenticator) Verify(token string) (Identity, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (any, error) {
+		if t.Method.Alg() != a.cfg.Alg {
+			return nil, fmt.Errorf("unexpected signing method %q", t.Method.Alg())
+		}
+		if a.cfg.Alg == "HS256" {
+			return a.cfg.Secret, nil
+		}
+		return a.cfg.PublicKey, nil
+	}, jwt.WithIssuer("iris"))
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	var id int
+	if _, err := fmt.Sscanf(c.Subject, "%d", &id); err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	return Identity{UserID: id, Email: c.Email, Scopes: strings.Fields(c.Scope)}, nil
+}
+
+// ctxKey keys the authenticated identity on the request context.
+type ctxKey struct{}
+
+// Identity is the authenticated caller attached to the context.
+type Identity struct {
+	UserID int      `json:"user_id"`
+	Email  string   `json:"email"`
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// HasScope reports whether the identity holds scope. The "admin"
+// scope implies every other.
+func (id Identity) HasScope(scope string) bool {
+	for _, s := range id.Scopes {
+		if s == scope || s == "admin" {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireScope returns middleware admitting only identities that hold
+// scope, for routes to declare their needs at registration:
+//
+//	mux.HandleFunc("/users", auth.RequireScope("users:write")(h))
+func RequireScope(scope string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			id, ok := FromContext(r.Context())
+			if !ok || !id.HasScope(scope) {
+				Error(w, r, http.StatusForbidden, "insufficient scope")
+				return
+			}
+			next(w, r)
+		}
+	}
+}
+
+// WithIdentity returns ctx carrying the authenticated identity.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the authenticated identity, if any.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(ctxKey{}).(Identity)
+	return id, ok
+}
+
+// Middleware rejects requests without a valid bearer token and
+// injects the caller's identity into the request context.
+func (a *Authenticator) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="iris"`)
+			Error(w, r, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		id, err := a.Verify(token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="iris", error="invalid_token"`)
+			Error(w, r, http.StatusUnauthorized, "invalid or expired token")
+			return
+		}
+		ctx := WithIdentity(r.Context(), id)
+		next(w, r.WithContext(ctx))
+	}
+}
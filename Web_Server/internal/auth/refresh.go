@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrTokenReused is returned when a refresh token that was already
+// rotated is presented again — the classic sign of a stolen token.
+// The whole token family is revoked when this happens.
+var ErrTokenReused = errors.New("auth: refresh token reuse detected")
+
+// refreshRecord tracks one issued refresh token. Tokens form families
+// across rotations so reuse of any superseded member revokes them all.
+type refreshRecord struct {
+	identity Identity
+	family   string
+	expires  time.Time
+
+	// rotated marks a token that has been exchanged for a successor;
+	// presenting it again triggers reuse detection.
+	rotated bool
+}
+
+// RefreshStore keeps refresh tokens server-side so they can be
+// rotated and revoked. Tokens are opaque random strings; nothing is
+// derivable from them offline.
+type RefreshStore struct {
+	mu     sync.Mutex
+	tokens map[string]*refreshRecord
+	ttl    time.Duration
+}
+
+// NewRefreshStore returns a store whose tokens expire after ttl.
+func NewRefreshStore(ttl time.Duration) *RefreshStore {
+	if ttl <= 0 {
+		ttl = 30 * 24 * time.Hour
+	}
+	return &RefreshStore{tokens: make(map[string]*refreshRecord), ttl: ttl}
+}
+
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("auth: generating token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Issue mints a fresh token (and token family) for a login.
+func (s *RefreshStore) Issue(id Identity) (string, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	family, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweepLocked()
+	s.tokens[token] = &refreshRecord{
+		identity: id,
+		family:   family,
+		expires:  time.Now().Add(s.ttl),
+	}
+	return token, nil
+}
+
+// Rotate exchanges a valid token for a new one in the same family and
+// returns the identity it stands for. A rotated token presented twice
+// revokes its entire family and returns ErrTokenReused.
+func (s *RefreshStore) Rotate(token string) (string, Identity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.tokens[token]
+	if !ok || time.Now().After(rec.expires) {
+		delete(s.tokens, token)
+		return "", Identity{}, ErrInvalidToken
+	}
+	if rec.rotated {
+		s.revokeFamilyLocked(rec.family)
+		return "", Identity{}, ErrTokenReused
+	}
+	next, err := randomToken()
+	if err != nil {
+		return "", Identity{}, err
+	}
+	rec.rotated = true
+	s.tokens[next] = &refreshRecord{
+		identity: rec.identity,
+		family:   rec.family,
+		expires:  time.Now().Add(s.ttl),
+	}
+	return next, rec.identity, nil
+}
+
+// Revoke invalidates a token and every other member of its family,
+// e.g. on logout.
+func (s *RefreshStore) Revoke(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rec, ok := s.tokens[token]
+	if !ok {
+		return
+	}
+	s.revokeFamilyLocked(rec.family)
+}
+
+func (s *RefreshStore) revokeFamilyLocked(family string) {
+	for t, rec := range s.tokens {
+		if rec.family == family {
+			delete(s.tokens, t)
+		}
+	}
+}
+
+// sweepLocked drops expired tokens; called opportunistically so the
+// map does not grow without bound.
+func (s *RefreshStore) sweepLocked() {
+	now := time.Now()
+	for t, rec := range s.tokens {
+		if now.After(rec.expires) {
+			delete(s.tokens, t)
+		}
+	}
+}
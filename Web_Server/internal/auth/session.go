@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SessionCookie is the name of the session cookie.
+const SessionCookie = "iris_session"
+
+// SessionStore persists opaque session IDs server-side. Implementations
+// must be safe for concurrent use.
+type SessionStore interface {
+	// Create stores a new session and returns its ID.
+	Create(ctx context.Context, id Identity, ttl time.Duration) (string, error)
+
+	// Get resolves a session ID to its identity, or ErrInvalidToken
+	// for unknown or expired sessions.
+	Get(ctx context.Context, sid string) (Identity, error)
+
+	// Destroy removes a session; destroying a missing session is not
+	// an error.
+	Destroy(ctx context.Context, sid string) error
+}
+
+// MemorySessions is the default SessionStore: a mutex-guarded map,
+// fine for a single instance.
+type MemorySessions struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+}
+
+type memorySession struct {
+	identity Identity
+	expires  time.Time
+}
+
+// NewMemorySessions returns an empty in-memory session store.
+func NewMemorySessions() *MemorySessions {
+	return &MemorySessions{sessions: make(map[string]memorySession)}
+}
+
+func (m *MemorySessions) Create(_ context.Context, id Identity, ttl time.Duration) (string, error) {
+	sid, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for k, s := range m.sessions {
+		if now.After(s.expires) {
+			delete(m.sessions, k)
+		}
+	}
+	m.sessions[sid] = memorySession{identity: id, expires: now.Add(ttl)}
+	return sid, nil
+}
+
+func (m *MemorySessions) Get(_ context.Context, sid string) (Identity, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[sid]
+	if !ok || time.Now().After(s.expires) {
+		delete(m.sessions, sid)
+		return Identity{}, ErrInvalidToken
+	}
+	return s.identity, nil
+}
+
+func (m *MemorySessions) Destroy(_ context.Context, sid string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, sid)
+	return nil
+}
+
+// RedisSessions stores sessions in Redis so they survive restarts and
+// are shared between instances.
+type RedisSessions struct {
+	client *redis.Client
+}
+
+// NewRedisSessions connects to the Redis server at addr.
+func NewRedisSessions(ctx context.Context, addr string) (*RedisSessions, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &RedisSessions{client: client}, nil
+}
+
+func redisKey(sid string) string { return "iris:session:" + sid }
+
+func (r *RedisSessions) Create(ctx context.Context, id Identity, ttl time.Duration) (string, error) {
+	sid, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	raw, err := json.Marshal(id)
+	if err != nil {
+		return "", err
+	}
+	if err := r.client.Set(ctx, redisKey(sid), raw, ttl).Err(); err != nil {
+		return "", err
+	}
+	return sid, nil
+}
+
+func (r *RedisSessions) Get(ctx context.Context, sid string) (Identity, error) {
+	raw, err := r.client.Get(ctx, redisKey(sid)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Identity{}, ErrInvalidToken
+	}
+	if err != nil {
+		return Identity{}, err
+	}
+	var id Identity
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return Identity{}, err
+	}
+	return id, nil
+}
+
+func (r *RedisSessions) Destroy(ctx context.Context, sid string) error {
+	return r.client.Del(ctx, redisKey(sid)).Err()
+}
+
+// Sessions is the cookie-based alternative to JWT auth: opaque session
+// IDs in secure, HttpOnly cookies, resolvable and revocable server-side.
+type Sessions struct {
+	store SessionStore
+	ttl   time.Duration
+
+	// Secure marks issued cookies Secure; disable only for local
+	// plain-HTTP development.
+	Secure bool
+}
+
+// NewSessions wraps a SessionStore with cookie handling.
+func NewSessions(store SessionStore, ttl time.Duration) *Sessions {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &Sessions{store: store, ttl: ttl, Secure: true}
+}
+
+// Login creates a session for id and sets the cookie on w.
+func (s *Sessions) Login(ctx context.Context, w http.ResponseWriter, id Identity) error {
+	sid, err := s.store.Create(ctx, id, s.ttl)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    sid,
+		Path:     "/",
+		MaxAge:   int(s.ttl.Seconds()),
+		HttpOnly: true,
+		Secure:   s.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// Logout destroys the request's session, if any, and expires the
+// cookie.
+func (s *Sessions) Logout(w http.ResponseWriter, r *http.Request) error {
+	if c, err := r.Cookie(SessionCookie); err == nil {
+		if err := s.store.Destroy(r.Context(), c.Value); err != nil {
+			return err
+		}
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     SessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return nil
+}
+
+// Middleware rejects requests without a valid session cookie and
+// injects the caller's identity into the request context.
+func (s *Sessions) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie(SessionCookie)
+		if err != nil {
+			Error(w, r, http.StatusUnauthorized, "authentication required")
+			return
+		}
+		id, err := s.store.Get(r.Context(), c.Value)
+		if err != nil {
+			Error(w, r, http.StatusUnauthorized, "invalid or expired session")
+			return
+		}
+		next(w, r.WithContext(WithIdentity(r.Context(), id)))
+	}
+}
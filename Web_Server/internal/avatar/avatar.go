@@ -0,0 +1,141 @@
+// Package avatar processes uploaded profile images in the background:
+// uploads are enqueued to a worker pool which crops, resizes, and
+// re-encodes each variant before storing it via the blob store.
+package avatar
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"log"
+	"sync"
+
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"golang.org/x/image/draw"
+
+	"github.com/divijg19/Iris/Web_Server/internal/blob"
+)
+
+// Variants maps each variant name to the square pixel size it is
+// rendered at. "original" is stored as uploaded (re-encoded to PNG)
+// and is not listed here.
+var Variants = map[string]int{
+	"thumb":  128,
+	"medium": 512,
+}
+
+// ErrQueueFull is returned by Enqueue when the processing queue is at
+// capacity; callers should surface it as a retryable condition.
+var ErrQueueFull = errors.New("avatar: processing queue full")
+
+// Key returns the blob key for one variant of a user's avatar.
+func Key(userID int, variant string) string {
+	return fmt.Sprintf("avatars/%d/%s.png", userID, variant)
+}
+
+type job struct {
+	userID int
+	data   []byte
+}
+
+// Processor resizes and stores avatar variants asynchronously.
+type Processor struct {
+	blobs blob.Store
+	jobs  chan job
+	wg    sync.WaitGroup
+}
+
+// NewProcessor starts workers goroutines draining a queue of at most
+// queue pending uploads.
+func NewProcessor(blobs blob.Store, workers, queue int) *Processor {
+	p := &Processor{
+		blobs: blobs,
+		jobs:  make(chan job, queue),
+	}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	return p
+}
+
+// Enqueue schedules the raw uploaded image for processing. It never
+// blocks: if the queue is full, ErrQueueFull is returned.
+func (p *Processor) Enqueue(userID int, data []byte) error {
+	select {
+	case p.jobs <- job{userID: userID, data: data}:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Close stops accepting new jobs and blocks until queued jobs finish.
+func (p *Processor) Close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+func (p *Processor) worker() {
+	defer p.wg.Done()
+	for j := range p.jobs {
+		if err := p.process(j); err != nil {
+			log.Printf("avatar: processing upload for user %d: %v", j.userID, err)
+		}
+	}
+}
+
+func (p *Processor) process(j job) error {
+	src, _, err := image.Decode(bytes.NewReader(j.data))
+	if err != nil {
+		return fmt.Errorf("decoding image: %w", err)
+	}
+
+	ctx := context.Background()
+	if err := p.store(ctx, j.userID, "original", src); err != nil {
+		return err
+	}
+	for variant, size := range Variants {
+		if err := p.store(ctx, j.userID, variant, scale(crop(src), size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Processor) store(ctx context.Context, userID int, variant string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("encoding %s: %w", variant, err)
+	}
+	if err := p.blobs.Put(ctx, Key(userID, variant), &buf, int64(buf.Len()), "image/png"); err != nil {
+		return fmt.Errorf("storing %s: %w", variant, err)
+	}
+	return nil
+}
+
+// crop returns the largest centered square of img.
+func crop(img image.Image) image.Image {
+	b := img.Bounds()
+	side := b.Dx()
+	if b.Dy() < side {
+		side = b.Dy()
+	}
+	x := b.Min.X + (b.Dx()-side)/2
+	y := b.Min.Y + (b.Dy()-side)/2
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), img, image.Pt(x, y), draw.Src)
+	return dst
+}
+
+// scale resizes a square image to size×size pixels.
+func scale(img image.Image, size int) image.Image {
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return dst
+}
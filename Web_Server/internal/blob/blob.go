@@ -0,0 +1,33 @@
+// Package blob abstracts binary object storage (avatars, export
+// archives) behind a small interface so the backing store can be a
+// local directory in development and an object store in production.
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned by Get and Delete when no object exists
+// under the requested key.
+var ErrNotFound = errors.New("blob: object not found")
+
+// Store reads and writes binary objects addressed by key. Keys use
+// forward slashes as separators, e.g. "avatars/42/thumb.png".
+type Store interface {
+	// Put stores the object read from r under key, replacing any
+	// existing object. size may be -1 when unknown.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the object stored under key. The caller must close
+	// the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the keys of all objects whose key begins with
+	// prefix, in lexical order.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
@@ -0,0 +1,84 @@
+package cache
+
+import "sort"
+
+// EntryInfo is the admin endpoints' view of one cached entry: the
+// value itself, how long it has left, and how often it has been hit —
+// the three questions a stale-data investigation asks.
+type EntryInfo struct {
+	Key     string `json:"key"`
+	Value   any    `json:"value"`
+	Expires bool   `json:"expires"`
+	TTL     string `json:"ttl,omitempty"`
+	Hits    uint64 `json:"hits"`
+}
+
+// Admin bundles the string-keyed operations the cache admin endpoints
+// need. Like the stats and TTL registrations it works through
+// closures, since the registry cannot see a cache's type parameters.
+type Admin struct {
+	Flush   func()
+	Delete  func(key string) bool
+	Keys    func() []string
+	Inspect func(key string) (EntryInfo, bool)
+}
+
+// NewAdmin adapts a cache onto the Admin surface; parse and format
+// convert between the wire's string keys and the cache's own.
+func NewAdmin[K comparable, V any](c *Cache[K, V], parse func(string) (K, error), format func(K) string) Admin {
+	return Admin{
+		Flush: c.Clear,
+		Delete: func(key string) bool {
+			k, err := parse(key)
+			if err != nil {
+				return false
+			}
+			if _, _, _, ok := c.Info(k); !ok {
+				return false
+			}
+			c.Delete(k)
+			return true
+		},
+		Keys: func() []string {
+			keys := c.Keys()
+			out := make([]string, len(keys))
+			for i, k := range keys {
+				out[i] = format(k)
+			}
+			sort.Strings(out)
+			return out
+		},
+		Inspect: func(key string) (EntryInfo, bool) {
+			k, err := parse(key)
+			if err != nil {
+				return EntryInfo{}, false
+			}
+			v, ttl, hits, ok := c.Info(k)
+			if !ok {
+				return EntryInfo{}, false
+			}
+			info := EntryInfo{Key: key, Value: v, Hits: hits}
+			if ttl > 0 {
+				info.Expires = true
+				info.TTL = ttl.String()
+			}
+			return info, true
+		},
+	}
+}
+
+// RegisterAdmin exposes a cache's admin operations under name; call
+// once at startup alongside Register.
+func RegisterAdmin(name string, a Admin) {
+	registry.mu.Lock()
+	registry.admin[name] = a
+	registry.mu.Unlock()
+}
+
+// AdminOf looks up the admin surface of the named cache.
+func AdminOf(name string) (Admin, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	a, ok := registry.admin[name]
+	return a, ok
+}
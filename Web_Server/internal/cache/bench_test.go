@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+// benchmarkPolicy drives the cache with a Zipf-distributed key stream
+// — a small hot set over a much larger key space, which is what the
+// admission policies disagree about — and reports the hit rate next
+// to the usual ns/op.
+func benchmarkPolicy(b *testing.B, p Policy) {
+	c := New[uint64, int]()
+	c.SetCapacity(1 << 10)
+	c.SetPolicy(p)
+
+	zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.2, 1, 1<<16)
+	keys := make([]uint64, 1<<18)
+	for i := range keys {
+		keys[i] = zipf.Uint64()
+	}
+
+	var hits int
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		k := keys[i%len(keys)]
+		if _, ok := c.Get(k); ok {
+			hits++
+		} else {
+			c.Set(k, i, 0)
+		}
+	}
+	b.ReportMetric(float64(hits)/float64(b.N), "hit-rate")
+}
+
+func BenchmarkLRU(b *testing.B) {
+	benchmarkPolicy(b, LRU)
+}
+
+func BenchmarkTinyLFU(b *testing.B) {
+	benchmarkPolicy(b, TinyLFU)
+}
+
+// BenchmarkGetParallel reads a fully resident working set from every
+// available CPU — the floor for hit latency with no misses or
+// admission decisions in the way.
+func BenchmarkGetParallel(b *testing.B) {
+	c := New[uint64, int]()
+	c.SetCapacity(1 << 12)
+	for i := uint64(0); i < 1<<12; i++ {
+		c.Set(i, int(i), 0)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var state uint64 = 1
+		for pb.Next() {
+			state = state*1664525 + 1013904223
+			if _, ok := c.Get(state % (1 << 12)); !ok {
+				b.Fatal("resident key missed")
+			}
+		}
+	})
+}
+
+// BenchmarkSetParallel overwrites resident keys from every CPU, the
+// worst case for shard write-lock hold times.
+func BenchmarkSetParallel(b *testing.B) {
+	c := New[uint64, int]()
+	c.SetCapacity(1 << 12)
+	for i := uint64(0); i < 1<<12; i++ {
+		c.Set(i, int(i), 0)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		var state uint64 = 1
+		for pb.Next() {
+			state = state*1664525 + 1013904223
+			k := state % (1 << 12)
+			c.Set(k, int(k), 0)
+		}
+	})
+}
+
+// BenchmarkSharded measures a mixed workload (one Set per seven Gets
+// over a working set twice the capacity) as goroutines pile on, which
+// is where the per-shard locks earn their keep.
+func BenchmarkSharded(b *testing.B) {
+	for _, g := range []int{1, 8, 64} {
+		b.Run(fmt.Sprintf("goroutines=%d", g), func(b *testing.B) {
+			benchmarkConcurrent(b, g)
+		})
+	}
+}
+
+func benchmarkConcurrent(b *testing.B, goroutines int) {
+	c := New[uint64, int]()
+	c.SetCapacity(1 << 12)
+	for i := uint64(0); i < 1<<12; i++ {
+		c.Set(i, int(i), 0)
+	}
+
+	per := b.N/goroutines + 1
+	var wg sync.WaitGroup
+	b.ResetTimer()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(state uint64) {
+			defer wg.Done()
+			for i := 0; i < per; i++ {
+				state = state*1664525 + 1013904223
+				k := state % (1 << 13)
+				if k&7 == 0 {
+					c.Set(k, int(k), 0)
+				} else {
+					c.Get(k)
+				}
+			}
+		}(uint64(g) + 1)
+	}
+	wg.Wait()
+}
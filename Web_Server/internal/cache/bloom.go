@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"math"
+	"sync"
+)
+
+// Bloom is a set-membership filter with one-sided error: a false from
+// MayContain is definitive, a true might be a collision. Fronting a
+// store with one lets lookups for keys that were never written skip
+// the backend entirely. Keys cannot be removed — that would take
+// counting slots — so a deleted key stays "maybe present", which only
+// costs the lookup the filter would have saved.
+type Bloom struct {
+	mu   sync.RWMutex
+	bits []uint64
+	m    uint64 // filter size in bits
+	k    int    // probes per key
+}
+
+// NewBloom sizes the filter for roughly expected keys at a 1% false-
+// positive rate. Overfilling past that degrades the rate gradually
+// rather than breaking anything.
+func NewBloom(expected int) *Bloom {
+	if expected < 1 {
+		expected = 1
+	}
+	// The usual optimum: m = -n·ln p / ln²2 bits, k = (m/n)·ln 2
+	// probes per key.
+	const p = 0.01
+	m := uint64(math.Ceil(-float64(expected) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := int(math.Round(float64(m) / float64(expected) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &Bloom{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+// Add marks key as present.
+func (b *Bloom) Add(key string) {
+	h := hashString(key)
+	h2 := h>>32 | h<<32
+	b.mu.Lock()
+	for i := 0; i < b.k; i++ {
+		idx := (h + uint64(i)*h2) % b.m
+		b.bits[idx/64] |= 1 << (idx % 64)
+	}
+	b.mu.Unlock()
+}
+
+// MayContain reports whether key might have been added; false means
+// it definitely was not.
+func (b *Bloom) MayContain(key string) bool {
+	h := hashString(key)
+	h2 := h>>32 | h<<32
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for i := 0; i < b.k; i++ {
+		idx := (h + uint64(i)*h2) % b.m
+		if b.bits[idx/64]&(1<<(idx%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,255 @@
+// Package cache provides a small in-memory key/value cache with
+// per-entry TTLs, bounded LRU or TinyLFU retention, and internal
+// sharding, replacing the ad-hoc map-plus-mutex pairs that grow
+// around every piece of short-lived state.
+package cache
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Policy picks how a full cache decides what stays.
+//
+// LRU is pure recency: every new key is admitted and the least
+// recently used one leaves. It is the cheapest option and the right
+// one when access patterns follow time (sessions, nonces).
+//
+// TinyLFU keeps the LRU eviction order but adds a frequency-based
+// admission filter: a new key only displaces the current victim when
+// a count-min sketch says it has been touched more often. That makes
+// the cache scan-resistant — a one-off sweep over cold keys cannot
+// flush the hot set — at the cost of hashing every touch and a few
+// kilobytes of sketch, and a genuinely new hot key needs a second
+// touch before it gets in.
+type Policy int
+
+const (
+	LRU Policy = iota
+	TinyLFU
+)
+
+// Cache maps keys to values that expire. The zero TTL means an entry
+// lives until deleted. With a capacity set, the selected policy
+// decides what a full cache keeps. Keys are hashed across a power-of-
+// two number of shards, each with its own lock, so concurrent callers
+// rarely contend; retention is therefore approximate — each shard
+// bounds and orders only its own slice of the key space. Safe for
+// concurrent use.
+type Cache[K comparable, V any] struct {
+	shards  []*shard[K, V]
+	mask    uint64
+	sliding bool
+
+	// flights tracks in-progress GetOrLoad calls per key; stale is
+	// the soft-TTL window for stale-while-revalidate.
+	flightMu sync.Mutex
+	flights  map[K]*flight[V]
+	stale    time.Duration
+
+	// loads times every loader call GetOrLoad makes, for the metrics
+	// endpoint's duration histogram.
+	loads *loadHist
+}
+
+// New returns an empty, unbounded cache with one shard per available
+// CPU (rounded up to a power of two).
+func New[K comparable, V any]() *Cache[K, V] {
+	n := 1
+	for n < runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	shards := make([]*shard[K, V], n)
+	for i := range shards {
+		shards[i] = newShard[K, V]()
+	}
+	return &Cache[K, V]{shards: shards, mask: uint64(n - 1), flights: make(map[K]*flight[V]), loads: newLoadHist()}
+}
+
+func (c *Cache[K, V]) shard(key K) *shard[K, V] {
+	if len(c.shards) == 1 {
+		return c.shards[0]
+	}
+	return c.shards[hashKey(key)&c.mask]
+}
+
+// SetCapacity bounds the cache to roughly n entries, split evenly
+// across the shards; n <= 0 removes the bound. Call before the cache
+// sees traffic.
+func (c *Cache[K, V]) SetCapacity(n int) {
+	per := n
+	if n > 0 {
+		per = (n + len(c.shards) - 1) / len(c.shards)
+	}
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.capacity = per
+		s.mu.Unlock()
+	}
+}
+
+// SetMaxCost bounds the cache by total cost instead of (or on top of)
+// entry count: cost prices each entry — typically its bytes — and the
+// selected policy evicts until the total fits the budget, split
+// evenly across the shards like SetCapacity's. An entry costing more
+// than a whole shard's budget is never admitted. The cost function
+// runs under the shard lock on every store, so it must be fast and
+// must not touch the cache; budget <= 0 removes the bound. Call
+// before the cache sees traffic.
+func (c *Cache[K, V]) SetMaxCost(budget int64, cost func(K, V) int64) {
+	per := budget
+	if budget > 0 {
+		n := int64(len(c.shards))
+		per = (budget + n - 1) / n
+	} else {
+		cost = nil
+	}
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.maxCost = per
+		s.costFn = cost
+		s.mu.Unlock()
+	}
+}
+
+// SetPolicy selects the admission policy. TinyLFU needs the capacity
+// to size its sketches, so call SetCapacity first; like the other
+// knobs, set it before the cache sees traffic.
+func (c *Cache[K, V]) SetPolicy(p Policy) {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		if p == TinyLFU && s.capacity > 0 {
+			s.freq = newSketch(s.capacity)
+		} else {
+			s.freq = nil
+		}
+		s.mu.Unlock()
+	}
+}
+
+// OnSet registers fn to run after every store or replace of an entry.
+// Like all hooks it runs asynchronously on its own goroutine with
+// panics contained, so it may log, emit metrics, fire webhooks, or
+// touch the cache again without slowing the caller. Call before the
+// cache sees traffic.
+func (c *Cache[K, V]) OnSet(fn func(K, V)) {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.onSet = fn
+		s.mu.Unlock()
+	}
+}
+
+// OnEvict registers fn to run for each entry pushed out by the
+// capacity bound (not for expiries or Deletes). Same asynchronous,
+// panic-contained contract as OnSet; call before the cache sees
+// traffic.
+func (c *Cache[K, V]) OnEvict(fn func(K, V)) {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.onEvict = fn
+		s.mu.Unlock()
+	}
+}
+
+// OnExpire registers fn to run when an entry's TTL runs out — at the
+// access or janitor sweep that notices, not the expiry instant. Same
+// asynchronous, panic-contained contract as OnSet; call before the
+// cache sees traffic.
+func (c *Cache[K, V]) OnExpire(fn func(K, V)) {
+	for _, s := range c.shards {
+		s.mu.Lock()
+		s.onExpire = fn
+		s.mu.Unlock()
+	}
+}
+
+// Evictions counts the entries the capacity bound has pushed out so
+// far, for the metrics endpoint.
+func (c *Cache[K, V]) Evictions() uint64 {
+	var n uint64
+	for _, s := range c.shards {
+		s.mu.Lock()
+		n += s.evictions
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// SetSliding switches the cache to sliding expiration: every Get of a
+// live entry restarts its clock at the TTL it was stored with, so
+// entries expire from idleness rather than age. Right for session-like
+// state; wrong when the TTL bounds staleness, since a hot entry would
+// never refresh. Like the other knobs, set it before the cache sees
+// traffic.
+func (c *Cache[K, V]) SetSliding(on bool) {
+	c.sliding = on
+}
+
+// Set stores value under key for ttl; ttl <= 0 keeps it until deleted
+// or evicted. An existing entry is replaced, TTL included, and counts
+// as freshly used.
+func (c *Cache[K, V]) Set(key K, value V, ttl time.Duration) {
+	c.shard(key).set(key, value, ttl)
+}
+
+// Get returns the live value under key, marking it as recently used.
+// An expired entry counts as absent and is dropped on the way out.
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	return c.shard(key).get(key, c.sliding)
+}
+
+// Touch resets a live entry's expiry to ttl from now (ttl <= 0 makes
+// it permanent) without reading or replacing the value, and reports
+// whether the entry was there to extend. It counts as use for the
+// eviction order but not toward the hit/miss ratio.
+func (c *Cache[K, V]) Touch(key K, ttl time.Duration) bool {
+	return c.shard(key).touch(key, ttl)
+}
+
+// TTL reports the time a live entry has left without renewing it even
+// under sliding expiration; zero with ok=true means it never expires.
+func (c *Cache[K, V]) TTL(key K) (time.Duration, bool) {
+	return c.shard(key).remaining(key)
+}
+
+// Keys lists the live keys across shards, without promoting anything.
+// It is a debugging aid; the snapshot is already stale on return.
+func (c *Cache[K, V]) Keys() []K {
+	now := time.Now()
+	var keys []K
+	for _, s := range c.shards {
+		keys = s.keys(now, keys)
+	}
+	return keys
+}
+
+// Info reports a live entry's value, remaining TTL (zero for an entry
+// that never expires), and hit count without counting as use — an
+// inspection must not reorder the eviction queue it is inspecting.
+func (c *Cache[K, V]) Info(key K) (V, time.Duration, uint64, bool) {
+	return c.shard(key).info(key)
+}
+
+// Delete removes key; removing an absent key is a no-op.
+func (c *Cache[K, V]) Delete(key K) {
+	c.shard(key).delete(key)
+}
+
+// Len counts the live entries across shards. Expired entries awaiting
+// their lazy removal are not counted.
+func (c *Cache[K, V]) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.len()
+	}
+	return n
+}
+
+// Clear drops every entry without running the eviction callback.
+func (c *Cache[K, V]) Clear() {
+	for _, s := range c.shards {
+		s.clear()
+	}
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Codec turns cache values into bytes and back for the tiers that
+// leave the process — the Redis-backed Distributed fleet, or values
+// persisted to disk. The in-process shards hold live Go values and
+// never touch one.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// The built-in codecs. JSON is the default: self-describing and
+// inspectable from redis-cli, at the cost of size. Gob is Go-only and
+// pays for a type description on every value, since each cache entry
+// is its own stream. Msgpack is the compact middle ground and still
+// readable to non-Go consumers.
+var (
+	JSONCodec    Codec = jsonCodec{}
+	GobCodec     Codec = gobCodec{}
+	MsgpackCodec Codec = msgpackCodec{}
+)
+
+// CodecNamed resolves a configuration name ("json", "gob",
+// "msgpack") to its codec.
+func CodecNamed(name string) (Codec, bool) {
+	switch name {
+	case "json":
+		return JSONCodec, true
+	case "gob":
+		return GobCodec, true
+	case "msgpack":
+		return MsgpackCodec, true
+	}
+	return nil, false
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v any) error { return msgpack.Unmarshal(data, v) }
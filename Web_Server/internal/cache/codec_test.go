@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// TestCodecsRoundTripUser pushes a fully populated and a minimal user
+// through every codec. Timestamps are compared by instant rather than
+// DeepEqual, since codecs legitimately differ on the location they
+// decode into.
+func TestCodecsRoundTripUser(t *testing.T) {
+	deleted := time.Date(2026, time.March, 1, 12, 30, 0, 0, time.UTC)
+	full := models.User{
+		ID:           7,
+		UUID:         "018f2d5e-7c1a-7b3c-9d4e-0123456789ab",
+		Name:         "Ada Lovelace",
+		Email:        "ada@example.com",
+		Age:          36,
+		PasswordHash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+		DeletedAt:    &deleted,
+	}
+	minimal := models.User{ID: 1, Name: "Root", Email: "root@example.com"}
+
+	codecs := []struct {
+		name  string
+		codec Codec
+	}{
+		{"json", JSONCodec},
+		{"gob", GobCodec},
+		{"msgpack", MsgpackCodec},
+	}
+	for _, tc := range codecs {
+		for _, want := range []models.User{full, minimal} {
+			raw, err := tc.codec.Marshal(want)
+			if err != nil {
+				t.Fatalf("%s: marshal user %d: %v", tc.name, want.ID, err)
+			}
+			var got models.User
+			if err := tc.codec.Unmarshal(raw, &got); err != nil {
+				t.Fatalf("%s: unmarshal user %d: %v", tc.name, want.ID, err)
+			}
+			if (got.DeletedAt == nil) != (want.DeletedAt == nil) {
+				t.Fatalf("%s: user %d DeletedAt = %v, want %v", tc.name, want.ID, got.DeletedAt, want.DeletedAt)
+			}
+			if want.DeletedAt != nil {
+				if !got.DeletedAt.Equal(*want.DeletedAt) {
+					t.Fatalf("%s: user %d DeletedAt = %v, want %v", tc.name, want.ID, got.DeletedAt, want.DeletedAt)
+				}
+				got.DeletedAt, want.DeletedAt = nil, nil
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("%s: round trip = %+v, want %+v", tc.name, got, want)
+			}
+		}
+	}
+}
+
+// TestCodecNamed covers the configuration lookup, including the
+// rejection that surfaces as an invalid-env error at startup.
+func TestCodecNamed(t *testing.T) {
+	for _, name := range []string{"json", "gob", "msgpack"} {
+		if _, ok := CodecNamed(name); !ok {
+			t.Errorf("CodecNamed(%q) not found", name)
+		}
+	}
+	if c, ok := CodecNamed("xml"); ok {
+		t.Errorf("CodecNamed(\"xml\") = %v, want not found", c)
+	}
+}
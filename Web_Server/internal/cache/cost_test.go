@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"fmt"
+	"testing"
+)
+
+// byLen prices a string entry at its value's length, the natural cost
+// function for byte-bounded caches.
+func byLen(_ int, v string) int64 { return int64(len(v)) }
+
+// totalCost sums the shards' live cost accounting.
+func totalCost(c *Cache[int, string]) int64 {
+	var n int64
+	for _, s := range c.shards {
+		s.mu.Lock()
+		n += s.cost
+		s.mu.Unlock()
+	}
+	return n
+}
+
+// perShardBudget mirrors SetMaxCost's split, for asserting against
+// what each shard was actually given.
+func perShardBudget(c *Cache[int, string], budget int64) int64 {
+	n := int64(len(c.shards))
+	return (budget + n - 1) / n
+}
+
+func TestMaxCostEvicts(t *testing.T) {
+	c := New[int, string]()
+	const budget = 1 << 12
+	c.SetMaxCost(budget, byLen)
+
+	// Far more value bytes than the budget allows; evictions must keep
+	// every shard inside its share.
+	small := make([]byte, 64)
+	for i := 0; i < 1024; i++ {
+		c.Set(i, string(small), 0)
+	}
+	per := perShardBudget(c, budget)
+	for i, s := range c.shards {
+		s.mu.Lock()
+		got := s.cost
+		s.mu.Unlock()
+		if got > per {
+			t.Errorf("shard %d holds cost %d, budget %d", i, got, per)
+		}
+	}
+	if c.Evictions() == 0 {
+		t.Error("expected evictions after overfilling the budget")
+	}
+}
+
+func TestMixedSizesStayUnderBudget(t *testing.T) {
+	c := New[int, string]()
+	const budget = 1 << 14
+	c.SetMaxCost(budget, byLen)
+
+	// Interleave tiny and large values so the eviction loop has to
+	// pop a varying number of victims per insert.
+	for i := 0; i < 2048; i++ {
+		size := 16
+		if i%7 == 0 {
+			size = 2048
+		}
+		c.Set(i, string(make([]byte, size)), 0)
+	}
+	if got, limit := totalCost(c), perShardBudget(c, budget)*int64(len(c.shards)); got > limit {
+		t.Errorf("total cost %d exceeds %d", got, limit)
+	}
+}
+
+func TestOversizedEntryRejected(t *testing.T) {
+	c := New[int, string]()
+	const budget = 1 << 10
+	c.SetMaxCost(budget, byLen)
+
+	c.Set(1, string(make([]byte, 2*budget)), 0)
+	if _, ok := c.Get(1); ok {
+		t.Error("entry costing more than the whole budget was admitted")
+	}
+	c.Set(2, "small", 0)
+	if _, ok := c.Get(2); !ok {
+		t.Error("ordinary entry rejected")
+	}
+}
+
+func TestReplaceAdjustsCost(t *testing.T) {
+	c := New[int, string]()
+	c.SetMaxCost(1<<20, byLen)
+
+	c.Set(1, string(make([]byte, 100)), 0)
+	c.Set(1, string(make([]byte, 300)), 0)
+	if got := totalCost(c); got != 300 {
+		t.Errorf("cost after replace = %d, want 300", got)
+	}
+	c.Delete(1)
+	if got := totalCost(c); got != 0 {
+		t.Errorf("cost after delete = %d, want 0", got)
+	}
+}
+
+func TestCostWithEntryCapacity(t *testing.T) {
+	c := New[int, string]()
+	c.SetCapacity(len(c.shards) * 4)
+	c.SetMaxCost(1<<20, byLen)
+
+	for i := 0; i < 256; i++ {
+		c.Set(i, fmt.Sprintf("value-%d", i), 0)
+	}
+	for i, s := range c.shards {
+		s.mu.Lock()
+		n := s.ll.Len()
+		s.mu.Unlock()
+		if n > 4 {
+			t.Errorf("shard %d holds %d entries, capacity 4", i, n)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Distributed is a client-side sharded cache over a fleet of Redis
+// nodes: the consistent-hash ring decides which node owns each key,
+// so horizontally scaled instances agree on placement without any
+// coordinator, and growing or shrinking the fleet only re-homes the
+// keys adjacent to the changed node's ring points. Values are opaque
+// bytes; whatever encoding the caller picks travels through intact.
+type Distributed struct {
+	mu    sync.RWMutex
+	ring  *Ring
+	nodes map[string]*redis.Client
+}
+
+// NewDistributed connects to every node up front, failing fast on an
+// unreachable one like the process's other Redis clients do.
+func NewDistributed(ctx context.Context, addrs []string) (*Distributed, error) {
+	d := &Distributed{ring: NewRing(0), nodes: make(map[string]*redis.Client)}
+	for _, addr := range addrs {
+		if err := d.AddNode(ctx, addr); err != nil {
+			d.Close()
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// AddNode joins addr to the fleet after a ping proves it reachable.
+// Keys the ring re-homes onto it simply miss there until their next
+// fill; nothing is migrated.
+func (d *Distributed) AddNode(ctx context.Context, addr string) error {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.nodes[addr]; ok {
+		client.Close()
+		return nil
+	}
+	d.nodes[addr] = client
+	d.ring.Add(addr)
+	return nil
+}
+
+// RemoveNode drops addr from the fleet, handing its slice of the key
+// space to the ring neighbours. Entries left on the node age out by
+// their TTLs.
+func (d *Distributed) RemoveNode(addr string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	client, ok := d.nodes[addr]
+	if !ok {
+		return
+	}
+	delete(d.nodes, addr)
+	d.ring.Remove(addr)
+	client.Close()
+}
+
+// client resolves the node owning key.
+func (d *Distributed) client(key string) (*redis.Client, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	addr, ok := d.ring.Locate(key)
+	if !ok {
+		return nil, false
+	}
+	client, ok := d.nodes[addr]
+	return client, ok
+}
+
+// errNoNodes reports an operation against an empty fleet.
+var errNoNodes = errors.New("cache: no distributed cache nodes")
+
+// Get fetches key from its owning node; ok is false for a key that is
+// not there, which is not an error.
+func (d *Distributed) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	client, ok := d.client(key)
+	if !ok {
+		return nil, false, errNoNodes
+	}
+	raw, err := client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return raw, true, nil
+}
+
+// Set stores value under key on its owning node for ttl; ttl <= 0
+// keeps it until the node evicts it.
+func (d *Distributed) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	client, ok := d.client(key)
+	if !ok {
+		return errNoNodes
+	}
+	return client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete removes key from its owning node.
+func (d *Distributed) Delete(ctx context.Context, key string) error {
+	client, ok := d.client(key)
+	if !ok {
+		return errNoNodes
+	}
+	return client.Del(ctx, key).Err()
+}
+
+// Nodes lists the fleet, for introspection endpoints.
+func (d *Distributed) Nodes() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.ring.Nodes()
+}
+
+// Close disconnects from every node.
+func (d *Distributed) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var first error
+	for addr, client := range d.nodes {
+		if err := client.Close(); err != nil && first == nil {
+			first = err
+		}
+		delete(d.nodes, addr)
+		d.ring.Remove(addr)
+	}
+	return first
+}
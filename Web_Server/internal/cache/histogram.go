@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+// loadBounds are the histogram bucket upper bounds in seconds, spaced
+// for backend round trips: sub-millisecond answers from a warm remote
+// tier up through multi-second stalls worth alerting on.
+var loadBounds = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// Histogram is a snapshot of observed load durations. Counts holds
+// one non-cumulative observation count per bound plus a final
+// overflow bucket; a renderer wanting Prometheus's cumulative form
+// sums as it goes.
+type Histogram struct {
+	Bounds []float64
+	Counts []uint64
+	Sum    float64
+	Count  uint64
+}
+
+// loadHist accumulates load durations under its own lock rather than
+// a shard's — loads run outside the shard locks anyway.
+type loadHist struct {
+	mu     sync.Mutex
+	counts []uint64
+	sum    float64
+	n      uint64
+}
+
+func newLoadHist() *loadHist {
+	return &loadHist{counts: make([]uint64, len(loadBounds)+1)}
+}
+
+func (h *loadHist) observe(d time.Duration) {
+	secs := d.Seconds()
+	i := 0
+	for i < len(loadBounds) && secs > loadBounds[i] {
+		i++
+	}
+	h.mu.Lock()
+	h.counts[i]++
+	h.sum += secs
+	h.n++
+	h.mu.Unlock()
+}
+
+func (h *loadHist) snapshot() Histogram {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return Histogram{
+		Bounds: loadBounds,
+		Counts: append([]uint64(nil), h.counts...),
+		Sum:    h.sum,
+		Count:  h.n,
+	}
+}
@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// StartJanitor sweeps expired entries in the background every
+// interval, with ±10% jitter so several caches in one process don't
+// sweep in lockstep, until ctx is cancelled. Without a janitor an
+// expired entry lingers — invisible, but holding memory — until its
+// key happens to be read again.
+func (c *Cache[K, V]) StartJanitor(ctx context.Context, interval time.Duration) {
+	go func() {
+		for {
+			jitter := time.Duration((rand.Float64() - 0.5) * 0.2 * float64(interval))
+			timer := time.NewTimer(interval + jitter)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				c.sweep(time.Now())
+			}
+		}
+	}()
+}
+
+// sweep drops every entry already expired at now, counting them as
+// expirations. Shards are swept one at a time so a sweep never stalls
+// more than one lock.
+func (c *Cache[K, V]) sweep(now time.Time) {
+	for _, s := range c.shards {
+		s.sweep(now)
+	}
+}
+
+func (s *shard[K, V]) sweep(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for el := s.ll.Back(); el != nil; {
+		prev := el.Prev()
+		e := el.Value.(*entry[K, V])
+		if !e.live(now) {
+			s.ll.Remove(el)
+			delete(s.entries, e.key)
+			s.cost -= e.cost
+			s.expirations++
+			notify(s.onExpire, e.key, e.value)
+		}
+		el = prev
+	}
+}
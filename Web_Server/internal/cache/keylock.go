@@ -0,0 +1,47 @@
+package cache
+
+import (
+	"runtime"
+	"sync"
+)
+
+// KeyLock serializes work per key without a global lock: keys hash
+// onto a fixed set of striped mutexes, so two operations on the same
+// key always queue while operations on different keys almost never
+// meet. The compromise is false sharing — distinct keys landing on
+// one stripe serialize needlessly — kept rare by allocating far more
+// stripes than CPUs. It is the tool for read-modify-write sequences
+// against a store that only offers last-write-wins updates.
+type KeyLock[K comparable] struct {
+	stripes []sync.Mutex
+	mask    uint64
+}
+
+// NewKeyLock returns a lock striped sixteen ways per available CPU,
+// rounded up to a power of two.
+func NewKeyLock[K comparable]() *KeyLock[K] {
+	n := 1
+	for n < 16*runtime.GOMAXPROCS(0) {
+		n <<= 1
+	}
+	return &KeyLock[K]{stripes: make([]sync.Mutex, n), mask: uint64(n - 1)}
+}
+
+// Lock acquires the stripe owning key, blocking while any key on the
+// same stripe is held.
+func (l *KeyLock[K]) Lock(key K) {
+	l.stripes[hashKey(key)&l.mask].Lock()
+}
+
+// Unlock releases key's stripe.
+func (l *KeyLock[K]) Unlock(key K) {
+	l.stripes[hashKey(key)&l.mask].Unlock()
+}
+
+// Do runs fn while holding key's stripe, for callers without an early
+// return to thread an Unlock past.
+func (l *KeyLock[K]) Do(key K, fn func()) {
+	l.Lock(key)
+	defer l.Unlock(key)
+	fn()
+}
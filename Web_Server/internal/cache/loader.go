@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// flight is one in-progress load that concurrent callers for the same
+// key wait on instead of loading again.
+type flight[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// GetOrLoad returns the value under key, calling load on a miss and
+// caching the result for roughly ttl. It replaces the hand-written
+// check-miss-load-store dance, and adds two herd defenses: concurrent
+// misses for one key share a single load (the rest wait on the first
+// caller's result), and the stored TTL is jittered ±10% so entries
+// loaded together do not expire together and stampede the backend in
+// sync. Load errors are returned to every waiter and not cached.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, ttl time.Duration, load func(context.Context) (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		if c.stale > 0 {
+			if rem, live := c.TTL(key); live && rem > 0 && rem <= c.stale {
+				c.refresh(key, ttl, load)
+			}
+		}
+		return v, nil
+	}
+	c.flightMu.Lock()
+	if f, ok := c.flights[key]; ok {
+		c.flightMu.Unlock()
+		select {
+		case <-f.done:
+			return f.val, f.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+	f := &flight[V]{done: make(chan struct{})}
+	c.flights[key] = f
+	c.flightMu.Unlock()
+
+	start := time.Now()
+	f.val, f.err = load(ctx)
+	c.loads.observe(time.Since(start))
+	if f.err == nil {
+		c.Set(key, f.val, jitterTTL(ttl)+c.stale)
+	}
+	c.flightMu.Lock()
+	delete(c.flights, key)
+	c.flightMu.Unlock()
+	close(f.done)
+	return f.val, f.err
+}
+
+// LoadStats reports the durations of every loader call so far, failed
+// loads included — they hold the request just the same.
+func (c *Cache[K, V]) LoadStats() Histogram {
+	return c.loads.snapshot()
+}
+
+// SetStaleWhileRevalidate softens GetOrLoad's TTL: entries live window
+// longer than asked, and a hit inside that grace period is served
+// immediately while one background goroutine refreshes the entry from
+// the loader. Hot keys then never pay the reload on the request path —
+// they pay bounded staleness instead. A failed refresh keeps serving
+// the stale value until the hard expiry. Pointless combined with
+// sliding expiration, which already keeps hot entries from expiring.
+// Call before the cache sees traffic.
+func (c *Cache[K, V]) SetStaleWhileRevalidate(window time.Duration) {
+	c.stale = window
+}
+
+// refresh reloads key in the background, deduplicated through the
+// same flight table as GetOrLoad so a popular stale entry triggers
+// one load, not one per hit.
+func (c *Cache[K, V]) refresh(key K, ttl time.Duration, load func(context.Context) (V, error)) {
+	c.flightMu.Lock()
+	if _, ok := c.flights[key]; ok {
+		c.flightMu.Unlock()
+		return
+	}
+	f := &flight[V]{done: make(chan struct{})}
+	c.flights[key] = f
+	c.flightMu.Unlock()
+
+	go func() {
+		start := time.Now()
+		f.val, f.err = load(context.Background())
+		c.loads.observe(time.Since(start))
+		if f.err == nil {
+			c.Set(key, f.val, jitterTTL(ttl)+c.stale)
+		}
+		c.flightMu.Lock()
+		delete(c.flights, key)
+		c.flightMu.Unlock()
+		close(f.done)
+	}()
+}
+
+// jitterTTL spreads a TTL ±10%, the same smear the janitor applies to
+// its sweep cadence.
+func jitterTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration((rand.Float64()-0.5)*0.2*float64(ttl))
+}
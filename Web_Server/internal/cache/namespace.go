@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Namespace scopes a slice of a string-keyed cache and stamps every
+// key with a generation number. Bump retires the whole group in O(1):
+// the generation moves on, every existing entry becomes unreachable,
+// and the orphans age out through TTL or eviction instead of being
+// scanned and deleted one by one. That is the right shape for "all
+// list queries are now suspect" moments — a bulk import, a purge —
+// where enumerating the affected keys would cost more than the cache
+// saves. Several namespaces can share one cache; their names keep the
+// key spaces apart.
+type Namespace[V any] struct {
+	cache *Cache[string, V]
+	name  string
+	gen   atomic.Uint64
+}
+
+// NewNamespace scopes c under name, starting at generation zero.
+func NewNamespace[V any](c *Cache[string, V], name string) *Namespace[V] {
+	return &Namespace[V]{cache: c, name: name}
+}
+
+// key stamps k with the namespace and current generation.
+func (n *Namespace[V]) key(k string) string {
+	return n.name + ":g" + strconv.FormatUint(n.gen.Load(), 10) + ":" + k
+}
+
+// Get returns the live value under key in the current generation.
+func (n *Namespace[V]) Get(key string) (V, bool) {
+	return n.cache.Get(n.key(key))
+}
+
+// Set stores value under key in the current generation for ttl. A
+// Bump racing a Set may strand the entry in the old generation, which
+// costs a refill, never a stale read.
+func (n *Namespace[V]) Set(key string, value V, ttl time.Duration) {
+	n.cache.Set(n.key(key), value, ttl)
+}
+
+// Delete removes key from the current generation.
+func (n *Namespace[V]) Delete(key string) {
+	n.cache.Delete(n.key(key))
+}
+
+// Bump invalidates every entry in the namespace at once.
+func (n *Namespace[V]) Bump() {
+	n.gen.Add(1)
+}
+
+// Generation reports the current generation, for introspection.
+func (n *Namespace[V]) Generation() uint64 {
+	return n.gen.Load()
+}
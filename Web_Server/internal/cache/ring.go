@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// defaultReplicas is how many virtual nodes represent each real one.
+// More points smooth the key distribution; 128 keeps the imbalance in
+// the low single digits without making lookups or membership changes
+// noticeably slower.
+const defaultReplicas = 128
+
+// Ring maps keys onto a set of named nodes with consistent hashing:
+// each node is hashed onto the circle many times (virtual nodes, so
+// load spreads evenly even with few real ones), and a key belongs to
+// the first node point at or after its own hash. Adding or removing a
+// node therefore only moves the keys adjacent to its points — about
+// 1/n of them — instead of reshuffling everything the way modular
+// hashing would. Safe for concurrent use.
+type Ring struct {
+	mu       sync.RWMutex
+	replicas int
+	hashes   []uint64 // sorted virtual-node positions
+	owner    map[uint64]string
+	nodes    map[string]struct{}
+}
+
+// NewRing returns an empty ring with the given number of virtual
+// nodes per real node; replicas <= 0 selects the default.
+func NewRing(replicas int) *Ring {
+	if replicas <= 0 {
+		replicas = defaultReplicas
+	}
+	return &Ring{
+		replicas: replicas,
+		owner:    make(map[uint64]string),
+		nodes:    make(map[string]struct{}),
+	}
+}
+
+// Add places node on the ring; adding a node twice is a no-op.
+func (r *Ring) Add(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.nodes[node]; ok {
+		return
+	}
+	r.nodes[node] = struct{}{}
+	for i := 0; i < r.replicas; i++ {
+		h := hashString(node + "#" + strconv.Itoa(i))
+		// On the vanishingly rare collision the earlier point keeps
+		// its spot, so lookups stay deterministic.
+		if _, taken := r.owner[h]; taken {
+			continue
+		}
+		r.owner[h] = node
+		r.hashes = append(r.hashes, h)
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+}
+
+// Remove takes node off the ring, handing its keys to the neighbours.
+func (r *Ring) Remove(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.nodes[node]; !ok {
+		return
+	}
+	delete(r.nodes, node)
+	kept := r.hashes[:0]
+	for _, h := range r.hashes {
+		if r.owner[h] == node {
+			delete(r.owner, h)
+			continue
+		}
+		kept = append(kept, h)
+	}
+	r.hashes = kept
+}
+
+// Locate names the node responsible for key; ok is false on an empty
+// ring.
+func (r *Ring) Locate(key string) (string, bool) {
+	h := hashString(key)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.hashes) == 0 {
+		return "", false
+	}
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0 // wrap past the top of the circle
+	}
+	return r.owner[r.hashes[i]], true
+}
+
+// Nodes lists the ring's members in stable order.
+func (r *Ring) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := make([]string, 0, len(r.nodes))
+	for n := range r.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
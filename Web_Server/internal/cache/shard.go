@@ -0,0 +1,297 @@
+package cache
+
+import (
+	"container/list"
+	"log"
+	"sync"
+	"time"
+)
+
+// shard is one independently locked slice of the cache: the LRU list,
+// the entry map, and (under TinyLFU) a frequency sketch of its own.
+// The exported Cache hashes keys across a set of these so concurrent
+// requests stop funneling through a single mutex.
+type shard[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	maxCost  int64            // total cost budget; 0 means unbounded
+	costFn   func(K, V) int64 // non-nil only with a cost bound
+	cost     int64            // sum of the live entries' costs
+	freq     *sketch          // non-nil only under TinyLFU
+	ll       *list.List       // most recently used at the front
+	entries  map[K]*list.Element
+	onSet    func(K, V)
+	onEvict  func(K, V)
+	onExpire func(K, V)
+
+	hits        uint64
+	misses      uint64
+	evictions   uint64
+	expirations uint64
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time     // zero means no expiry
+	ttl       time.Duration // the TTL it was stored with, for sliding expiry
+	cost      int64         // per the cost function; 0 without one
+	hits      uint64        // reads served by this entry, for inspection
+}
+
+// live reports whether the entry is still valid at now.
+func (e *entry[K, V]) live(now time.Time) bool {
+	return e.expiresAt.IsZero() || now.Before(e.expiresAt)
+}
+
+// notify fires a hook on its own goroutine, swallowing any panic it
+// raises. Hooks therefore never stall a cache operation, may safely
+// touch the cache again, and a broken one cannot take the process
+// down — but they observe changes slightly after the fact and in no
+// guaranteed order.
+func notify[K comparable, V any](fn func(K, V), key K, value V) {
+	if fn == nil {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("cache: hook panic: %v", r)
+			}
+		}()
+		fn(key, value)
+	}()
+}
+
+func newShard[K comparable, V any]() *shard[K, V] {
+	return &shard[K, V]{ll: list.New(), entries: make(map[K]*list.Element)}
+}
+
+func (s *shard[K, V]) set(key K, value V, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	s.mu.Lock()
+	var cost int64
+	if s.costFn != nil {
+		cost = s.costFn(key, value)
+	}
+	// An entry dearer than the whole budget would evict everything and
+	// still not fit, so it is never admitted.
+	if s.maxCost > 0 && cost > s.maxCost {
+		s.mu.Unlock()
+		return
+	}
+	var h uint64
+	if s.freq != nil {
+		h = hashKey(key)
+		s.freq.add(h)
+	}
+	if el, ok := s.entries[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value = value
+		e.expiresAt = expiresAt
+		e.ttl = ttl
+		s.cost += cost - e.cost
+		e.cost = cost
+		s.ll.MoveToFront(el)
+		notify(s.onSet, key, value)
+		s.evictLocked()
+		s.mu.Unlock()
+		return
+	}
+	// TinyLFU admission: a new key only gets in over a full shard —
+	// whether the count or the cost budget is what it would bust —
+	// when the sketch says it is hotter than the entry it would
+	// displace.
+	full := (s.capacity > 0 && s.ll.Len() >= s.capacity) ||
+		(s.maxCost > 0 && s.cost+cost > s.maxCost)
+	if s.freq != nil && full {
+		if el := s.ll.Back(); el != nil {
+			victim := el.Value.(*entry[K, V])
+			if s.freq.estimate(h) <= s.freq.estimate(hashKey(victim.key)) {
+				s.mu.Unlock()
+				return
+			}
+		}
+	}
+	s.entries[key] = s.ll.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt, ttl: ttl, cost: cost})
+	s.cost += cost
+	notify(s.onSet, key, value)
+	s.evictLocked()
+	s.mu.Unlock()
+}
+
+// over reports whether the shard currently exceeds either bound.
+func (s *shard[K, V]) over() bool {
+	return (s.capacity > 0 && s.ll.Len() > s.capacity) ||
+		(s.maxCost > 0 && s.cost > s.maxCost)
+}
+
+// evictLocked drops least recently used entries until the shard fits
+// its entry-count and cost budgets again, firing the eviction hook for
+// each. Hooks run on their own goroutines, so firing them under the
+// lock is safe.
+func (s *shard[K, V]) evictLocked() {
+	for s.over() {
+		el := s.ll.Back()
+		if el == nil {
+			break
+		}
+		e := el.Value.(*entry[K, V])
+		s.ll.Remove(el)
+		delete(s.entries, e.key)
+		s.cost -= e.cost
+		s.evictions++
+		notify(s.onEvict, e.key, e.value)
+	}
+}
+
+func (s *shard[K, V]) get(key K, sliding bool) (V, bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.freq != nil {
+		s.freq.add(hashKey(key))
+	}
+	el, ok := s.entries[key]
+	if !ok {
+		s.misses++
+		var zero V
+		return zero, false
+	}
+	e := el.Value.(*entry[K, V])
+	if !e.live(now) {
+		s.ll.Remove(el)
+		delete(s.entries, key)
+		s.cost -= e.cost
+		s.misses++
+		s.expirations++
+		notify(s.onExpire, key, e.value)
+		var zero V
+		return zero, false
+	}
+	if sliding && e.ttl > 0 {
+		e.expiresAt = now.Add(e.ttl)
+	}
+	s.ll.MoveToFront(el)
+	s.hits++
+	e.hits++
+	return e.value, true
+}
+
+// touch restarts an existing live entry's clock at ttl without reading
+// or replacing its value.
+func (s *shard[K, V]) touch(key K, ttl time.Duration) bool {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return false
+	}
+	e := el.Value.(*entry[K, V])
+	if !e.live(now) {
+		s.ll.Remove(el)
+		delete(s.entries, key)
+		s.cost -= e.cost
+		s.expirations++
+		notify(s.onExpire, key, e.value)
+		return false
+	}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	} else {
+		e.expiresAt = time.Time{}
+	}
+	e.ttl = ttl
+	s.ll.MoveToFront(el)
+	return true
+}
+
+// remaining reports the entry's time left without promoting it; zero
+// with ok means it never expires.
+func (s *shard[K, V]) remaining(key K) (time.Duration, bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	el, ok := s.entries[key]
+	if !ok {
+		return 0, false
+	}
+	e := el.Value.(*entry[K, V])
+	if !e.live(now) {
+		return 0, false
+	}
+	if e.expiresAt.IsZero() {
+		return 0, true
+	}
+	return e.expiresAt.Sub(now), true
+}
+
+// keys appends the shard's live keys to dst, newest first within the
+// shard, without promoting anything.
+func (s *shard[K, V]) keys(now time.Time, dst []K) []K {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for el := s.ll.Front(); el != nil; el = el.Next() {
+		if e := el.Value.(*entry[K, V]); e.live(now) {
+			dst = append(dst, e.key)
+		}
+	}
+	return dst
+}
+
+// info reports a live entry's value, remaining TTL (zero for none),
+// and hit count without promoting it.
+func (s *shard[K, V]) info(key K) (V, time.Duration, uint64, bool) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var zero V
+	el, ok := s.entries[key]
+	if !ok {
+		return zero, 0, 0, false
+	}
+	e := el.Value.(*entry[K, V])
+	if !e.live(now) {
+		return zero, 0, 0, false
+	}
+	var ttl time.Duration
+	if !e.expiresAt.IsZero() {
+		ttl = e.expiresAt.Sub(now)
+	}
+	return e.value, ttl, e.hits, true
+}
+
+func (s *shard[K, V]) delete(key K) {
+	s.mu.Lock()
+	if el, ok := s.entries[key]; ok {
+		s.ll.Remove(el)
+		delete(s.entries, key)
+		s.cost -= el.Value.(*entry[K, V]).cost
+	}
+	s.mu.Unlock()
+}
+
+func (s *shard[K, V]) len() int {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for el := s.ll.Front(); el != nil; el = el.Next() {
+		if el.Value.(*entry[K, V]).live(now) {
+			n++
+		}
+	}
+	return n
+}
+
+func (s *shard[K, V]) clear() {
+	s.mu.Lock()
+	s.ll.Init()
+	s.entries = make(map[K]*list.Element)
+	s.cost = 0
+	s.mu.Unlock()
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"fmt"
+	"hash/fnv"
+)
+
+// sketch is a four-row count-min sketch of 8-bit counters, the
+// frequency memory behind TinyLFU admission. It remembers roughly how
+// often each key has been touched in a few kilobytes regardless of key
+// cardinality; collisions only ever over-estimate, which at worst
+// admits a key that deserved rejection. Counters are halved after a
+// sample window of additions so long-dead hot keys age out.
+type sketch struct {
+	rows      [4][]uint8
+	mask      uint64
+	additions int
+	window    int
+}
+
+// newSketch sizes the sketch for a cache of the given capacity: eight
+// counters per entry, rounded up to a power of two, aged every ten
+// windows of capacity.
+func newSketch(capacity int) *sketch {
+	width := 1
+	for width < capacity*8 {
+		width <<= 1
+	}
+	s := &sketch{mask: uint64(width - 1), window: capacity * 10}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// add bumps the key's counters, saturating at 255.
+func (s *sketch) add(h uint64) {
+	h2 := h>>32 | h<<32
+	for i := range s.rows {
+		idx := (h + uint64(i)*h2) & s.mask
+		if s.rows[i][idx] < 255 {
+			s.rows[i][idx]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.window {
+		s.age()
+	}
+}
+
+// estimate reads the key's frequency: the minimum across rows, since
+// every collision inflates.
+func (s *sketch) estimate(h uint64) uint8 {
+	h2 := h>>32 | h<<32
+	est := uint8(255)
+	for i := range s.rows {
+		if c := s.rows[i][(h+uint64(i)*h2)&s.mask]; c < est {
+			est = c
+		}
+	}
+	return est
+}
+
+// age halves every counter, so frequency reflects the recent window
+// rather than all of history.
+func (s *sketch) age() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions = 0
+}
+
+// hashKey folds a comparable key to 64 bits, for shard routing and
+// the sketch. Strings and the common integer kinds take an inline
+// path; anything else rounds through fmt, which allocates per touch —
+// priced by the package benchmarks.
+func hashKey[K comparable](key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return hashString(k)
+	case int:
+		return mix64(uint64(k))
+	case int64:
+		return mix64(uint64(k))
+	case int32:
+		return mix64(uint64(k))
+	case uint64:
+		return mix64(k)
+	case uint32:
+		return mix64(uint64(k))
+	default:
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%v", key)
+		return h.Sum64()
+	}
+}
+
+// hashString is FNV-1a without the hash.Hash allocation.
+func hashString(s string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
+
+// mix64 is the splitmix64 finalizer. Integer keys are often
+// sequential, and without mixing the shard mask would see only their
+// low bits.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
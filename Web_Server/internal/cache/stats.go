@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// Stats is a point-in-time aggregate of a cache's counters.
+// EstimatedBytes prices the entry shells — key, value struct, list
+// element, and a map-bucket allowance — plus, under SetMaxCost, the
+// measured per-entry costs; without a cost function the heap behind
+// the values is invisible to a generic container and goes unpriced.
+type Stats struct {
+	Hits           uint64 `json:"hits"`
+	Misses         uint64 `json:"misses"`
+	Evictions      uint64 `json:"evictions"`
+	Expirations    uint64 `json:"expirations"`
+	Entries        int    `json:"entries"`
+	EstimatedBytes int64  `json:"estimated_bytes"`
+}
+
+// Stats sums the shard counters.
+func (c *Cache[K, V]) Stats() Stats {
+	var st Stats
+	for _, s := range c.shards {
+		one := shardStats(s)
+		st.Hits += one.Hits
+		st.Misses += one.Misses
+		st.Evictions += one.Evictions
+		st.Expirations += one.Expirations
+		st.Entries += one.Entries
+		st.EstimatedBytes += one.EstimatedBytes
+	}
+	return st
+}
+
+// ShardStats reports each shard's counters separately, in shard
+// order. An uneven spread of hits or entries across them is the
+// signature of a skewed key space — one hot key's shard serializing
+// while the rest idle.
+func (c *Cache[K, V]) ShardStats() []Stats {
+	out := make([]Stats, len(c.shards))
+	for i, s := range c.shards {
+		out[i] = shardStats(s)
+	}
+	return out
+}
+
+// shardStats reads one shard's counters under its lock.
+func shardStats[K comparable, V any](s *shard[K, V]) Stats {
+	const mapAllowance = 48 // rough per-entry map bucket share
+	perEntry := int64(unsafe.Sizeof(entry[K, V]{})) + int64(unsafe.Sizeof(list.Element{})) + mapAllowance
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := Stats{
+		Hits:        s.hits,
+		Misses:      s.misses,
+		Evictions:   s.evictions,
+		Expirations: s.expirations,
+		Entries:     s.ll.Len(),
+	}
+	if s.costFn != nil {
+		// The measured cost covers what the values point at, which
+		// the shell estimate below cannot see.
+		st.EstimatedBytes = s.cost
+	}
+	st.EstimatedBytes += int64(st.Entries) * perEntry
+	return st
+}
+
+// registry names the cache instances the admin and metrics endpoints
+// report on. Values are closures rather than an interface because
+// Cache is generic.
+var registry = struct {
+	mu     sync.RWMutex
+	m      map[string]func() Stats
+	shards map[string]func() []Stats
+	loads  map[string]func() Histogram
+	ttl    map[string]func(key string) (time.Duration, bool)
+	admin  map[string]Admin
+}{
+	m:      make(map[string]func() Stats),
+	shards: make(map[string]func() []Stats),
+	loads:  make(map[string]func() Histogram),
+	ttl:    make(map[string]func(key string) (time.Duration, bool)),
+	admin:  make(map[string]Admin),
+}
+
+// Register exposes a cache's stats under name; call once at startup.
+// Registering the same name again replaces the previous entry.
+func Register(name string, stats func() Stats) {
+	registry.mu.Lock()
+	registry.m[name] = stats
+	registry.mu.Unlock()
+}
+
+// RegisterShards exposes a cache's per-shard counters under name, for
+// the metrics endpoint's shard-labeled series.
+func RegisterShards(name string, fn func() []Stats) {
+	registry.mu.Lock()
+	registry.shards[name] = fn
+	registry.mu.Unlock()
+}
+
+// RegisterLoads exposes a cache's load-duration histogram under name.
+// Only caches fed through GetOrLoad have one worth reporting.
+func RegisterLoads(name string, fn func() Histogram) {
+	registry.mu.Lock()
+	registry.loads[name] = fn
+	registry.mu.Unlock()
+}
+
+// ShardSnapshot collects the per-shard stats of every cache that
+// registered them.
+func ShardSnapshot() map[string][]Stats {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	out := make(map[string][]Stats, len(registry.shards))
+	for name, fn := range registry.shards {
+		out[name] = fn()
+	}
+	return out
+}
+
+// LoadSnapshot collects the load histograms of every cache that
+// registered one.
+func LoadSnapshot() map[string]Histogram {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	out := make(map[string]Histogram, len(registry.loads))
+	for name, fn := range registry.loads {
+		out[name] = fn()
+	}
+	return out
+}
+
+// RegisterTTL exposes a per-key remaining-TTL lookup under name. The
+// key arrives in string form — the registry cannot see the cache's
+// key type — so the closure owns the conversion.
+func RegisterTTL(name string, fn func(key string) (time.Duration, bool)) {
+	registry.mu.Lock()
+	registry.ttl[name] = fn
+	registry.mu.Unlock()
+}
+
+// LookupTTL answers how long the named cache will keep key: known is
+// false for a cache with no TTL lookup registered, found for a key
+// that is not cached, and a zero ttl with found means the entry never
+// expires.
+func LookupTTL(name, key string) (ttl time.Duration, found, known bool) {
+	registry.mu.RLock()
+	fn, ok := registry.ttl[name]
+	registry.mu.RUnlock()
+	if !ok {
+		return 0, false, false
+	}
+	ttl, found = fn(key)
+	return ttl, found, true
+}
+
+// Snapshot collects the stats of every registered cache.
+func Snapshot() map[string]Stats {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	out := make(map[string]Stats, len(registry.m))
+	for name, fn := range registry.m {
+		out[name] = fn()
+	}
+	return out
+}
+
+// Names lists the registered caches in stable order, for endpoints
+// that render them one per line.
+func Names() []string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	names := make([]string, 0, len(registry.m))
+	for name := range registry.m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
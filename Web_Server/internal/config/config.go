@@ -0,0 +1,211 @@
+// Package config centralizes the server's tunables. The program has
+// always been configured through IRIS_* environment variables, so the
+// layered sources resolve into exactly that namespace: built-in
+// defaults are filled in first, a config file overrides them, real
+// environment variables override the file, and explicit Set calls
+// (flag overrides) sit on top. Load materializes the result into the
+// process environment, where every existing consumer already looks.
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvPrefix namespaces every setting this package manages.
+const EnvPrefix = "IRIS_"
+
+// defaults are the built-in values for settings that must always
+// resolve to something.
+var defaults = map[string]string{
+	"IRIS_ADDR":           ":8080",
+	"IRIS_STORE":          "memory",
+	"IRIS_LOG_LEVEL":      "info",
+	"IRIS_SHUTDOWN_GRACE": "15s",
+}
+
+// secretKey matches setting names whose values must never appear in
+// diagnostic output. DSNs carry passwords, so they count.
+var secretKey = regexp.MustCompile(`SECRET|PASSWORD|TOKEN|DSN|_KEY$|_KEYS$`)
+
+// Config is the resolved view of every IRIS_* setting.
+type Config struct {
+	values map[string]string
+	// pinned marks keys that outrank the file: real environment
+	// variables captured at startup and flag overrides. A reloaded
+	// file never displaces them.
+	pinned map[string]bool
+}
+
+// Load resolves the layered configuration, reading the file at path
+// when it is non-empty, and exports the result into the process
+// environment so os.Getenv callers see the merged view.
+func Load(path string) (*Config, error) {
+	c := &Config{values: map[string]string{}, pinned: map[string]bool{}}
+	for k, v := range defaults {
+		c.values[k] = v
+	}
+	if path != "" {
+		if err := loadFile(path, c.values); err != nil {
+			return nil, err
+		}
+	}
+	for _, kv := range os.Environ() {
+		k, v, _ := strings.Cut(kv, "=")
+		if strings.HasPrefix(k, EnvPrefix) {
+			c.values[k] = v
+			c.pinned[k] = true
+		}
+	}
+	for k, v := range c.values {
+		if _, present := os.LookupEnv(k); !present {
+			os.Setenv(k, v)
+		}
+	}
+	return c, nil
+}
+
+// Reload re-reads the config file and commits whatever changed,
+// returning the old and new value per changed key. Pinned keys (real
+// environment, flags) are untouched; keys dropped from the file fall
+// back to their defaults or disappear. A candidate that fails
+// validation is rejected wholesale, leaving the running configuration
+// as it was.
+func (c *Config) Reload(path string) (map[string][2]string, error) {
+	fresh := map[string]string{}
+	for k, v := range defaults {
+		fresh[k] = v
+	}
+	if path != "" {
+		if err := loadFile(path, fresh); err != nil {
+			return nil, err
+		}
+	}
+	for k := range c.pinned {
+		fresh[k] = c.values[k]
+	}
+	if err := (&Config{values: fresh}).Validate(); err != nil {
+		return nil, err
+	}
+
+	changed := map[string][2]string{}
+	for k, v := range fresh {
+		if old, ok := c.values[k]; !ok || old != v {
+			changed[k] = [2]string{c.values[k], v}
+			c.values[k] = v
+			os.Setenv(k, v)
+		}
+	}
+	for k, old := range c.values {
+		if _, ok := fresh[k]; !ok {
+			changed[k] = [2]string{old, ""}
+			delete(c.values, k)
+			os.Unsetenv(k)
+		}
+	}
+	return changed, nil
+}
+
+// loadFile reads a flat "key: value" file (a YAML subset that is also
+// valid INI with "="). Keys are lowercase without the prefix —
+// "store_dsn: data/iris.db" sets IRIS_STORE_DSN. Blank lines and
+// #-comments are skipped.
+func loadFile(path string, into map[string]string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		sep := strings.IndexAny(text, ":=")
+		if sep < 1 {
+			return fmt.Errorf("config: %s:%d: expected \"key: value\"", path, line)
+		}
+		key := strings.TrimSpace(text[:sep])
+		val := strings.Trim(strings.TrimSpace(text[sep+1:]), `"'`)
+		into[EnvPrefix+strings.ToUpper(key)] = val
+	}
+	return sc.Err()
+}
+
+// Set applies a top-precedence override (a command-line flag) and
+// pushes it through to the environment. Keys set this way are pinned:
+// a reloaded file cannot change them.
+func (c *Config) Set(key, value string) {
+	key = canonical(key)
+	c.values[key] = value
+	c.pinned[key] = true
+	os.Setenv(key, value)
+}
+
+// Get returns the resolved value for a setting; the key may be given
+// with or without the IRIS_ prefix.
+func (c *Config) Get(key string) string {
+	return c.values[canonical(key)]
+}
+
+// Validate rejects configurations the server could not start with.
+func (c *Config) Validate() error {
+	switch c.Get("store") {
+	case "memory", "sqlite", "bolt", "mongo", "mysql", "postgres":
+	default:
+		return fmt.Errorf("config: unknown store backend %q", c.Get("store"))
+	}
+	switch c.Get("log_level") {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("config: unknown log level %q", c.Get("log_level"))
+	}
+	for _, key := range []string{"IRIS_SHUTDOWN_GRACE", "IRIS_JWT_TTL", "IRIS_REFRESH_TTL", "IRIS_SESSION_TTL"} {
+		v, ok := c.values[key]
+		if !ok || v == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+			return fmt.Errorf("config: invalid duration %s=%q", key, v)
+		}
+	}
+	return nil
+}
+
+// Dump writes the effective configuration sorted by key, masking
+// anything that smells like a credential, for `iris config dump`.
+func (c *Config) Dump(w io.Writer) {
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := c.values[k]
+		if v != "" && secretKey.MatchString(k) {
+			v = "[REDACTED]"
+		}
+		fmt.Fprintf(w, "%s=%s\n", k, v)
+	}
+}
+
+// Secret reports whether a setting's value must be masked in logs and
+// diagnostic output.
+func Secret(key string) bool {
+	return secretKey.MatchString(canonical(key))
+}
+
+func canonical(key string) string {
+	key = strings.ToUpper(key)
+	if !strings.HasPrefix(key, EnvPrefix) {
+		key = EnvPrefix + key
+	}
+	return key
+}
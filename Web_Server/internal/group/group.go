@@ -0,0 +1,76 @@
+// Package group runs a set of related goroutines as one unit: start
+// them with Go, collect the first failure with Wait, and let that
+// failure cancel the shared context so the rest can stop early. It
+// is the startup counterpart to the pool package — a handful of
+// heterogeneous tasks that must all succeed, rather than a stream of
+// uniform ones.
+package group
+
+import (
+	"context"
+	"sync"
+)
+
+// Group is a collection of goroutines whose first error wins. The
+// zero value is usable but has no context to cancel; WithContext is
+// the usual constructor.
+type Group struct {
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	sem    chan struct{}
+
+	once sync.Once
+	err  error
+}
+
+// WithContext returns a group and a context derived from ctx that is
+// cancelled the first time a task fails or Wait returns. Tasks that
+// block should watch it so one failure ends the whole unit.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{cancel: cancel}, ctx
+}
+
+// SetLimit bounds how many tasks run at once; Go blocks while the
+// limit is reached. Call before the first Go.
+func (g *Group) SetLimit(n int) {
+	if n < 1 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go starts fn on its own goroutine. The first non-nil error is kept
+// for Wait and cancels the group's context; later errors are dropped,
+// since they are usually downstream of the first.
+func (g *Group) Go(fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+		if err := fn(); err != nil {
+			g.once.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel()
+				}
+			})
+		}
+	}()
+}
+
+// Wait blocks until every started task has returned, cancels the
+// group's context, and reports the first error.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	return g.err
+}
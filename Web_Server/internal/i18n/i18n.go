@@ -0,0 +1,115 @@
+// Package i18n localizes user-facing messages. Catalogs are keyed by
+// the canonical English text, so handlers keep writing plain English
+// and translation happens at the response edge; unknown messages fall
+// through untranslated.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is what negotiation falls back to; English messages
+// are their own catalog, so it needs no entries.
+const DefaultLocale = "en"
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]map[string]string{
+		// Spanish ships as the worked example; Register adds more.
+		"es": {
+			"authentication is not configured": "la autenticación no está configurada",
+			"email already in use":             "el correo ya está en uso",
+			"empty batch":                      "lote vacío",
+			"insufficient scope":               "permisos insuficientes",
+			"internal error":                   "error interno",
+			"invalid credentials":              "credenciales no válidas",
+			"invalid limit":                    "límite no válido",
+			"invalid offset":                   "desplazamiento no válido",
+			"invalid user id":                  "identificador de usuario no válido",
+			"method not allowed":               "método no permitido",
+			"no deleted user with that id":     "no hay ningún usuario eliminado con ese id",
+			"request body too large":           "el cuerpo de la solicitud es demasiado grande",
+			"user not found":                   "usuario no encontrado",
+		},
+	}
+)
+
+// Register adds messages to a locale's catalog, creating the locale
+// if needed. Later registrations win on key collisions.
+func Register(locale string, messages map[string]string) {
+	locale = strings.ToLower(locale)
+	mu.Lock()
+	defer mu.Unlock()
+	cat, ok := catalogs[locale]
+	if !ok {
+		cat = make(map[string]string, len(messages))
+		catalogs[locale] = cat
+	}
+	for k, v := range messages {
+		cat[k] = v
+	}
+}
+
+// Match negotiates the best supported locale from an Accept-Language
+// header value, honoring q-weights and region subtags ("es-MX"
+// matches the "es" catalog).
+func Match(header string) string {
+	type candidate struct {
+		tag string
+		q   float64
+		idx int
+	}
+	var cands []candidate
+	for i, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		cands = append(cands, candidate{tag: tag, q: q, idx: i})
+	}
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].q > cands[j].q })
+
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, c := range cands {
+		if c.q <= 0 {
+			continue
+		}
+		base := strings.ToLower(strings.SplitN(c.tag, "-", 2)[0])
+		if base == DefaultLocale || base == "*" {
+			return DefaultLocale
+		}
+		if _, ok := catalogs[base]; ok {
+			return base
+		}
+	}
+	return DefaultLocale
+}
+
+// Lookup returns the locale's translation of msg, reporting whether
+// the catalog had one.
+func Lookup(locale, msg string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	translated, ok := catalogs[strings.ToLower(locale)][msg]
+	return translated, ok
+}
+
+// T translates msg for locale, returning it unchanged when there is
+// no catalog entry.
+func T(locale, msg string) string {
+	if translated, ok := Lookup(locale, msg); ok {
+		return translated
+	}
+	return msg
+}
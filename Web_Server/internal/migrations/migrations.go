@@ -0,0 +1,191 @@
+// Package migrations applies versioned schema migrations to the SQL
+// store backends. Migration files are embedded in the binary under
+// sql/<dialect>/NNNN_name.{up,down}.sql and tracked in a
+// schema_version table.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed sql
+var files embed.FS
+
+// A migration is one numbered schema step for a dialect.
+type migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// load reads and pairs the embedded up/down files for dialect,
+// ordered by version.
+func load(dialect string) ([]migration, error) {
+	entries, err := files.ReadDir("sql/" + dialect)
+	if err != nil {
+		return nil, fmt.Errorf("migrations: unknown dialect %q: %w", dialect, err)
+	}
+	byVersion := map[int]*migration{}
+	for _, e := range entries {
+		name := e.Name()
+		base, ok := strings.CutSuffix(name, ".up.sql")
+		down := false
+		if !ok {
+			base, ok = strings.CutSuffix(name, ".down.sql")
+			down = true
+		}
+		if !ok {
+			return nil, fmt.Errorf("migrations: unexpected file %q", name)
+		}
+		num, rest, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("migrations: unversioned file %q", name)
+		}
+		version, err := strconv.Atoi(num)
+		if err != nil {
+			return nil, fmt.Errorf("migrations: bad version in %q: %w", name, err)
+		}
+		body, err := files.ReadFile("sql/" + dialect + "/" + name)
+		if err != nil {
+			return nil, err
+		}
+		m := byVersion[version]
+		if m == nil {
+			m = &migration{Version: version, Name: rest}
+			byVersion[version] = m
+		}
+		if down {
+			m.Down = string(body)
+		} else {
+			m.Up = string(body)
+		}
+	}
+
+	all := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		all = append(all, *m)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Version < all[j].Version })
+	return all, nil
+}
+
+func ensureVersionTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_version (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+// Version reports the highest applied migration version (0 when none).
+func Version(ctx context.Context, db *sql.DB) (int, error) {
+	if err := ensureVersionTable(ctx, db); err != nil {
+		return 0, fmt.Errorf("migrations: ensuring version table: %w", err)
+	}
+	var v sql.NullInt64
+	if err := db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_version`).Scan(&v); err != nil {
+		return 0, fmt.Errorf("migrations: reading version: %w", err)
+	}
+	return int(v.Int64), nil
+}
+
+// Migrate applies every pending up migration for dialect, in order,
+// each inside its own transaction.
+func Migrate(ctx context.Context, db *sql.DB, dialect string) error {
+	all, err := load(dialect)
+	if err != nil {
+		return err
+	}
+	current, err := Version(ctx, db)
+	if err != nil {
+		return err
+	}
+	for _, m := range all {
+		if m.Version <= current {
+			continue
+		}
+		if err := inTx(ctx, db, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.Up); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO schema_version (version, name) VALUES (`+placeholders(dialect, 2)+`)`,
+				m.Version, m.Name)
+			return err
+		}); err != nil {
+			return fmt.Errorf("migrations: applying %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// Rollback undoes the most recently applied migration.
+func Rollback(ctx context.Context, db *sql.DB, dialect string) error {
+	all, err := load(dialect)
+	if err != nil {
+		return err
+	}
+	current, err := Version(ctx, db)
+	if err != nil {
+		return err
+	}
+	if current == 0 {
+		return fmt.Errorf("migrations: nothing to roll back")
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		m := all[i]
+		if m.Version != current {
+			continue
+		}
+		if err := inTx(ctx, db, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, m.Down); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx,
+				`DELETE FROM schema_version WHERE version = `+placeholder(dialect, 1),
+				m.Version)
+			return err
+		}); err != nil {
+			return fmt.Errorf("migrations: rolling back %04d_%s: %w", m.Version, m.Name, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("migrations: no migration file for version %d", current)
+}
+
+func inTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// placeholder renders the dialect's bind placeholder for position n.
+func placeholder(dialect string, n int) string {
+	if dialect == "postgres" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+func placeholders(dialect string, count int) string {
+	parts := make([]string, count)
+	for i := range parts {
+		parts[i] = placeholder(dialect, i+1)
+	}
+	return strings.Join(parts, ", ")
+}
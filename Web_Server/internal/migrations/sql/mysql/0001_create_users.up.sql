@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS users (
+    id    INT AUTO_INCREMENT PRIMARY KEY,
+    name  VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    age   INT NOT NULL DEFAULT 0
+);
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN uuid VARCHAR(36) NOT NULL DEFAULT '';
+CREATE INDEX idx_users_uuid ON users (uuid);
@@ -0,0 +1,6 @@
+-- MySQL has no partial indexes; a generated column that goes NULL on
+-- soft delete gives the same "unique among live users" semantics,
+-- since unique indexes ignore NULLs.
+ALTER TABLE users
+    ADD COLUMN email_live VARCHAR(255) GENERATED ALWAYS AS (IF(deleted_at IS NULL, email, NULL)) STORED,
+    ADD UNIQUE INDEX users_email_unique (email_live);
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS users (
+    id    SERIAL PRIMARY KEY,
+    name  TEXT NOT NULL,
+    email TEXT NOT NULL,
+    age   INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN uuid TEXT NOT NULL DEFAULT '';
+CREATE INDEX idx_users_uuid ON users (uuid);
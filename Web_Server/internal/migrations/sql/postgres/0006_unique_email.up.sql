@@ -0,0 +1,3 @@
+-- Only live users must be unique by email; soft-deleted ones keep
+-- their address without blocking re-registration.
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_unique ON users (email) WHERE deleted_at IS NULL;
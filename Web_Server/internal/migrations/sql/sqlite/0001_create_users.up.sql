@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS users (
+    id    INTEGER PRIMARY KEY AUTOINCREMENT,
+    name  TEXT NOT NULL,
+    email TEXT NOT NULL,
+    age   INTEGER NOT NULL DEFAULT 0
+);
@@ -0,0 +1,163 @@
+// Package pipeline composes channel-connected processing stages:
+// map, filter, batch, fan-out, and fan-in, each running on its own
+// goroutine. Every stage closes its output when its input closes or
+// the context is cancelled, so teardown flows downstream and a
+// pipeline never leaks goroutines past its context. Stages that can
+// fail report on a separate error channel; FanIn merges those the
+// same way it merges values.
+package pipeline
+
+import (
+	"context"
+	"sync"
+)
+
+// Emit feeds values into a fresh channel and closes it after the
+// last, the usual head of a pipeline.
+func Emit[T any](ctx context.Context, values ...T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for _, v := range values {
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Map applies fn to every value. A value fn fails on is dropped from
+// the output and its error sent on the second channel; consumers must
+// drain both, or the stage stalls on the first undrained send.
+func Map[T, R any](ctx context.Context, in <-chan T, fn func(context.Context, T) (R, error)) (<-chan R, <-chan error) {
+	out := make(chan R)
+	errs := make(chan error)
+	go func() {
+		defer close(out)
+		defer close(errs)
+		for v := range in {
+			r, err := fn(ctx, v)
+			if err != nil {
+				select {
+				case errs <- err:
+					continue
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, errs
+}
+
+// Filter passes along only the values keep approves.
+func Filter[T any](ctx context.Context, in <-chan T, keep func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for v := range in {
+			if !keep(v) {
+				continue
+			}
+			select {
+			case out <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Batch groups consecutive values into slices of up to size, flushing
+// a short final batch when the input closes. It is the stage in front
+// of anything with per-call overhead — bulk inserts, webhook posts.
+func Batch[T any](ctx context.Context, in <-chan T, size int) <-chan []T {
+	if size < 1 {
+		size = 1
+	}
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		batch := make([]T, 0, size)
+		flush := func() bool {
+			if len(batch) == 0 {
+				return true
+			}
+			select {
+			case out <- batch:
+				batch = make([]T, 0, size)
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		for v := range in {
+			batch = append(batch, v)
+			if len(batch) == size && !flush() {
+				return
+			}
+		}
+		flush()
+	}()
+	return out
+}
+
+// FanOut splits one input across n output channels, each value going
+// to whichever branch is free — the way to put slow per-value work on
+// multiple goroutines. Order across branches is not preserved.
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n < 1 {
+		n = 1
+	}
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			for v := range in {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	return outs
+}
+
+// FanIn merges channels into one, closing the result after the last
+// input closes. Merging the error channels of parallel Map branches
+// is the same call.
+func FanIn[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	var wg sync.WaitGroup
+	wg.Add(len(ins))
+	for _, in := range ins {
+		go func(in <-chan T) {
+			defer wg.Done()
+			for v := range in {
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(in)
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
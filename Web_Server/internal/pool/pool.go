@@ -0,0 +1,120 @@
+// Package pool provides a fixed-size generic worker pool: tasks go
+// in through Submit, a bounded set of goroutines applies one function
+// to each, and results come out a channel. It grew out of the worker
+// loop every background job here kept hand-rolling around channels
+// and a WaitGroup.
+package pool
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// Pool runs submitted T tasks on a fixed number of workers, emitting
+// one R per completed task on Results. A task that panics is logged
+// and produces no result; the workers survive it. Safe for concurrent
+// use.
+type Pool[T, R any] struct {
+	fn      func(context.Context, T) R
+	ctx     context.Context
+	jobs    chan T
+	results chan R
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	stopped bool
+}
+
+// New starts workers goroutines applying fn to submitted tasks. The
+// context is handed to every fn call and bounds the pool's life:
+// cancelling it makes workers finish their current task, drop
+// whatever is queued, and close Results. For an orderly end that
+// drains the queue instead, use Stop.
+func New[T, R any](ctx context.Context, workers int, fn func(context.Context, T) R) *Pool[T, R] {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &Pool[T, R]{
+		fn:      fn,
+		ctx:     ctx,
+		jobs:    make(chan T, 2*workers),
+		results: make(chan R, 2*workers),
+	}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	go func() {
+		p.wg.Wait()
+		close(p.results)
+	}()
+	return p
+}
+
+func (p *Pool[T, R]) worker() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case task, ok := <-p.jobs:
+			if !ok {
+				return
+			}
+			p.run(task)
+		}
+	}
+}
+
+// run applies fn to one task with panic containment, so a poisoned
+// task costs its own result and nothing else.
+func (p *Pool[T, R]) run(task T) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("pool: task panic: %v", r)
+		}
+	}()
+	res := p.fn(p.ctx, task)
+	select {
+	case p.results <- res:
+	case <-p.ctx.Done():
+	}
+}
+
+// Submit queues a task, blocking while the queue is full, and reports
+// whether the pool accepted it — false once Stop has been called or
+// the context is done.
+func (p *Pool[T, R]) Submit(task T) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped || p.ctx.Err() != nil {
+		return false
+	}
+	select {
+	case p.jobs <- task:
+		return true
+	case <-p.ctx.Done():
+		return false
+	}
+}
+
+// Results delivers one value per completed task. The channel closes
+// after Stop once the queue has drained, or once the context is
+// cancelled, so ranging over it is the natural way to collect.
+func (p *Pool[T, R]) Results() <-chan R {
+	return p.results
+}
+
+// Stop refuses new tasks and waits for the queued ones to finish.
+// Callers must keep draining Results while stopping — the last tasks
+// still need somewhere to put their values.
+func (p *Pool[T, R]) Stop() {
+	p.mu.Lock()
+	if !p.stopped {
+		p.stopped = true
+		close(p.jobs)
+	}
+	p.mu.Unlock()
+	p.wg.Wait()
+}
@@ -0,0 +1,76 @@
+// Package problem renders API errors as RFC 7807
+// application/problem+json documents, so every failure an iris
+// handler produces has the same machine-readable shape.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Problem is one RFC 7807 error document.
+type Problem struct {
+	// Type is a URI identifying the error class; "about:blank" (the
+	// RFC default) is implied when empty.
+	Type string `json:"type,omitempty"`
+
+	// Title is the short human-readable summary, normally the HTTP
+	// status text.
+	Title string `json:"title"`
+
+	// Status echoes the HTTP status code.
+	Status int `json:"status"`
+
+	// Detail explains this specific occurrence.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance identifies the request that failed, here the URL path.
+	Instance string `json:"instance,omitempty"`
+
+	// Fields maps input field names to validation messages.
+	Fields map[string]string `json:"fields,omitempty"`
+
+	// Extra holds extension members merged into the document at the
+	// top level (for example a body-size limit).
+	Extra map[string]any `json:"-"`
+}
+
+// New builds a problem for the given status with its standard title.
+func New(status int, detail string) Problem {
+	return Problem{Title: http.StatusText(status), Status: status, Detail: detail}
+}
+
+// MarshalJSON flattens Extra members into the document.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type plain Problem
+	raw, err := json.Marshal(plain(p))
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Extra) == 0 {
+		return raw, nil
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	for k, v := range p.Extra {
+		doc[k] = v
+	}
+	return json.Marshal(doc)
+}
+
+// Write renders the document with its status code and media type.
+func (p Problem) Write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
+
+// Error is the usual way handlers fail: it writes a problem document
+// for status with detail, stamping the request path as the instance.
+func Error(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	p := New(status, detail)
+	p.Instance = r.URL.Path
+	p.Write(w)
+}
@@ -0,0 +1,92 @@
+// Package ratelimit provides per-key token-bucket request limiting
+// with pluggable backing stores, so limits can live in process memory
+// or be shared across instances via Redis.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Store decides whether a key (normally a client IP) may make another
+// request right now.
+type Store interface {
+	// Allow reports whether the request may proceed and, when it may
+	// not, how long the caller should wait before retrying.
+	Allow(ctx context.Context, key string) (ok bool, retryAfter time.Duration, err error)
+}
+
+// Memory is the in-process Store: one token bucket per key, refilled
+// at rate tokens per second up to burst.
+type Memory struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewMemory returns a Store allowing rate requests per second with
+// the given burst capacity.
+func NewMemory(rate float64, burst int) *Memory {
+	if burst < 1 {
+		burst = 1
+	}
+	return &Memory{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// SetLimit retunes the bucket parameters in place. Existing buckets
+// keep their current tokens and refill at the new rate.
+func (m *Memory) SetLimit(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+	m.mu.Lock()
+	m.rate = rate
+	m.burst = float64(burst)
+	m.mu.Unlock()
+}
+
+func (m *Memory) Allow(_ context.Context, key string) (bool, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		// Piggyback an occasional sweep of idle buckets on misses so
+		// the map does not grow with every IP ever seen.
+		if len(m.buckets) > 10000 {
+			for k, old := range m.buckets {
+				if now.Sub(old.last) > time.Minute {
+					delete(m.buckets, k)
+				}
+			}
+		}
+		b = &bucket{tokens: m.burst, last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * m.rate
+	if b.tokens > m.burst {
+		b.tokens = m.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	wait := time.Duration((1 - b.tokens) / m.rate * float64(time.Second))
+	return false, wait, nil
+}
@@ -0,0 +1,74 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// allowScript implements a token bucket atomically in Redis. Keys hold
+// {tokens, last} and expire once idle long enough to refill fully.
+var allowScript = redis.NewScript(`
+local key = KEYS[1]
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local data = redis.call('HMGET', key, 'tokens', 'last')
+local tokens = tonumber(data[1])
+local last = tonumber(data[2])
+if tokens == nil then
+  tokens = burst
+  last = now
+end
+
+tokens = math.min(burst, tokens + (now - last) * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', key, 'tokens', tokens, 'last', now)
+redis.call('EXPIRE', key, math.ceil(burst / rate) + 1)
+if allowed == 1 then
+  return {1, '0'}
+end
+return {0, tostring((1 - tokens) / rate)}
+`)
+
+// Redis is a Store shared between instances through a Redis server.
+type Redis struct {
+	client *redis.Client
+	rate   float64
+	burst  int
+}
+
+// NewRedis connects to addr and returns a shared limiter with the
+// same semantics as the in-memory one.
+func NewRedis(ctx context.Context, addr string, rate float64, burst int) (*Redis, error) {
+	if burst < 1 {
+		burst = 1
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &Redis{client: client, rate: rate, burst: burst}, nil
+}
+
+func (r *Redis) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	res, err := allowScript.Run(ctx, r.client, []string{"iris:ratelimit:" + key},
+		r.rate, r.burst, float64(time.Now().UnixNano())/float64(time.Second)).Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	allowed := res[0].(int64) == 1
+	if allowed {
+		return true, 0, nil
+	}
+	waitSec, _ := time.ParseDuration(res[1].(string) + "s")
+	return false, waitSec, nil
+}
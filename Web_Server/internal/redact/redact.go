@@ -0,0 +1,84 @@
+// Package redact masks credential material before text reaches logs,
+// audit entries, or API error responses. It is deliberately eager: a
+// masked non-secret is a cosmetic bug, a leaked secret is an incident.
+package redact
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+const mask = "[REDACTED]"
+
+// rules match common credential shapes: JSON pairs for secret-ish
+// fields, key=value query fragments, HTTP bearer/basic credentials,
+// and bcrypt hashes.
+var rules = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)("(?:password|password_hash|token|refresh_token|access_token|secret|api_key)"\s*:\s*)"(?:[^"\\]|\\.)*"`), `${1}"` + mask + `"`},
+	{regexp.MustCompile(`(?i)\b((?:password|token|secret|api_key)=)[^&\s]+`), `${1}` + mask},
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]{8,}`), `${1} ` + mask},
+	{regexp.MustCompile(`\$2[aby]\$\d{2}\$[./A-Za-z0-9]{53}`), mask},
+}
+
+// sensitiveFields are struct/diff field names whose values are always
+// secrets regardless of shape.
+var sensitiveFields = map[string]bool{
+	"password":      true,
+	"password_hash": true,
+	"token":         true,
+	"refresh_token": true,
+	"access_token":  true,
+	"secret":        true,
+	"api_key":       true,
+}
+
+// sensitiveHeaders carry credentials wholesale.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"Proxy-Authorization": true,
+}
+
+// String masks every recognized credential in s.
+func String(s string) string {
+	for _, r := range rules {
+		s = r.re.ReplaceAllString(s, r.repl)
+	}
+	return s
+}
+
+// Error is String over err's message; safe on nil.
+func Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	return String(err.Error())
+}
+
+// Field reports whether a field of this name always holds a secret.
+func Field(name string) bool {
+	return sensitiveFields[strings.ToLower(name)]
+}
+
+// Headers returns a copy of h with credential-bearing headers masked,
+// suitable for request logging.
+func Headers(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for name, vals := range h {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			out[name] = []string{mask}
+			continue
+		}
+		out[name] = append([]string(nil), vals...)
+	}
+	return out
+}
+
+// Mask is the placeholder used for masked values.
+func Mask() string { return mask }
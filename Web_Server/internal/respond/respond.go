@@ -0,0 +1,30 @@
+// Package respond writes JSON responses so status line, headers, and
+// body always go out in a coherent order.
+package respond
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// JSON writes v with the given status. Any extra headers must be set
+// before the call, since the status line is committed here.
+func JSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if v != nil {
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+// Created answers 201 with a Location header for the new resource and
+// its representation as the body.
+func Created(w http.ResponseWriter, location string, v any) {
+	w.Header().Set("Location", location)
+	JSON(w, http.StatusCreated, v)
+}
+
+// NoContent answers 204.
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
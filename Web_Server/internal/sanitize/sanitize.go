@@ -0,0 +1,54 @@
+// Package sanitize cleans user-supplied strings before they are
+// stored, so downstream rendering does not have to remember to.
+package sanitize
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// HTMLMode says what to do with markup in free-text fields.
+type HTMLMode int
+
+const (
+	// HTMLKeep leaves markup alone; trimming and normalization still
+	// apply.
+	HTMLKeep HTMLMode = iota
+	// HTMLEscape entity-encodes markup so it renders as literal text.
+	HTMLEscape
+	// HTMLStrip removes tags outright.
+	HTMLStrip
+)
+
+// Policy is one configured pipeline. The zero value trims whitespace
+// and NFC-normalizes without touching markup.
+type Policy struct {
+	HTML HTMLMode
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Normalize trims surrounding whitespace, collapses interior runs of
+// it, and applies Unicode NFC so visually identical names compare
+// equal. It never alters markup; use Clean for display fields.
+func (Policy) Normalize(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	return norm.NFC.String(s)
+}
+
+// Clean runs the full pipeline for a free-text display field:
+// Normalize plus the configured HTML treatment.
+func (p Policy) Clean(s string) string {
+	s = p.Normalize(s)
+	switch p.HTML {
+	case HTMLEscape:
+		return html.EscapeString(s)
+	case HTMLStrip:
+		return strings.TrimSpace(tagPattern.ReplaceAllString(s, ""))
+	default:
+		return s
+	}
+}
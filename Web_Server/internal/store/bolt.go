@@ -0,0 +1,422 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// bucketUsers holds one JSON-encoded record per user, keyed by
+// big-endian ID so bucket iteration is ID-ordered.
+var bucketUsers = []byte("users")
+
+// Bolt is a Store backed by a bbolt file: durable, embedded, and
+// suited to single-binary deployments. All writes are atomic at the
+// transaction level.
+type Bolt struct {
+	db *bolt.DB
+}
+
+// NewBolt opens (creating if necessary) the database file at path.
+func NewBolt(path string) (*Bolt, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("store: opening bolt %q: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketUsers)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: creating bolt bucket: %w", err)
+	}
+	return &Bolt{db: db}, nil
+}
+
+// Close releases the file lock and flushes the database.
+func (b *Bolt) Close() error { return b.db.Close() }
+
+func boltKey(id int) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(id))
+	return k
+}
+
+// The bolt* helpers implement each operation against an open bucket,
+// shared by the plain methods and WithTx.
+
+func boltGet(bkt *bolt.Bucket, id int) (models.User, error) {
+	raw := bkt.Get(boltKey(id))
+	if raw == nil {
+		return models.User{}, ErrNotFound
+	}
+	var u models.User
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return models.User{}, err
+	}
+	if u.DeletedAt != nil {
+		return models.User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+func boltGetByUUID(bkt *bolt.Bucket, uuid string) (models.User, error) {
+	var (
+		u     models.User
+		found bool
+	)
+	err := bkt.ForEach(func(_, raw []byte) error {
+		if found {
+			return nil
+		}
+		var cand models.User
+		if err := json.Unmarshal(raw, &cand); err != nil {
+			return err
+		}
+		if cand.UUID == uuid && cand.DeletedAt == nil {
+			u, found = cand, true
+		}
+		return nil
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	if !found {
+		return models.User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+func boltGetByEmail(bkt *bolt.Bucket, email string) (models.User, error) {
+	var (
+		u     models.User
+		found bool
+	)
+	err := bkt.ForEach(func(_, raw []byte) error {
+		if found {
+			return nil
+		}
+		var cand models.User
+		if err := json.Unmarshal(raw, &cand); err != nil {
+			return err
+		}
+		if cand.Email == email && cand.DeletedAt == nil {
+			u, found = cand, true
+		}
+		return nil
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+	if !found {
+		return models.User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+func boltList(bkt *bolt.Bucket, opts ListOptions) ([]models.User, error) {
+	var all []models.User
+	err := bkt.ForEach(func(_, raw []byte) error {
+		var u models.User
+		if err := json.Unmarshal(raw, &u); err != nil {
+			return err
+		}
+		if u.DeletedAt != nil && !opts.IncludeDeleted {
+			return nil
+		}
+		if !opts.Matches(u) {
+			return nil
+		}
+		all = append(all, u)
+		return nil
+	})
+	SortUsers(all, opts.Sort)
+	return all, err
+}
+
+// boltEmailTaken reports whether a live user other than self holds
+// email. Writes are already O(n) over the bucket, so the scan costs
+// no more than the lookups above; running it inside the same update
+// transaction makes the check atomic with the write.
+func boltEmailTaken(bkt *bolt.Bucket, email string, self int) (bool, error) {
+	taken := false
+	err := bkt.ForEach(func(_, raw []byte) error {
+		if taken {
+			return nil
+		}
+		var cand models.User
+		if err := json.Unmarshal(raw, &cand); err != nil {
+			return err
+		}
+		if cand.Email == email && cand.DeletedAt == nil && cand.ID != self {
+			taken = true
+		}
+		return nil
+	})
+	return taken, err
+}
+
+func boltCreate(bkt *bolt.Bucket, u models.User) (models.User, error) {
+	taken, err := boltEmailTaken(bkt, u.Email, 0)
+	if err != nil {
+		return models.User{}, err
+	}
+	if taken {
+		return models.User{}, ErrConflict
+	}
+	seq, err := bkt.NextSequence()
+	if err != nil {
+		return models.User{}, err
+	}
+	u.ID = int(seq)
+	u.UUID = NewUUID()
+	u.Password = ""
+	u.DeletedAt = nil
+	raw, err := json.Marshal(u)
+	if err != nil {
+		return models.User{}, err
+	}
+	return u, bkt.Put(boltKey(u.ID), raw)
+}
+
+func boltUpdate(bkt *bolt.Bucket, u models.User) error {
+	old, err := boltGet(bkt, u.ID)
+	if err != nil {
+		return err
+	}
+	if old.Email != u.Email {
+		taken, err := boltEmailTaken(bkt, u.Email, u.ID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrConflict
+		}
+	}
+	u.UUID = old.UUID
+	u.Password = ""
+	u.PasswordHash = old.PasswordHash
+	raw, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(boltKey(u.ID), raw)
+}
+
+func boltSetDeleted(bkt *bolt.Bucket, id int, deleted bool) error {
+	raw := bkt.Get(boltKey(id))
+	if raw == nil {
+		return ErrNotFound
+	}
+	var u models.User
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return err
+	}
+	if deleted == (u.DeletedAt != nil) {
+		return ErrNotFound
+	}
+	if deleted {
+		now := time.Now().UTC()
+		u.DeletedAt = &now
+	} else {
+		// The address may have been re-registered while this user was
+		// deleted; coming back cannot evict the live holder.
+		taken, err := boltEmailTaken(bkt, u.Email, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrConflict
+		}
+		u.DeletedAt = nil
+	}
+	raw, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(boltKey(id), raw)
+}
+
+func (b *Bolt) Get(_ context.Context, id int) (models.User, error) {
+	var u models.User
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		u, err = boltGet(tx.Bucket(bucketUsers), id)
+		return err
+	})
+	return u, err
+}
+
+func (b *Bolt) GetByUUID(_ context.Context, uuid string) (models.User, error) {
+	var u models.User
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		u, err = boltGetByUUID(tx.Bucket(bucketUsers), uuid)
+		return err
+	})
+	return u, err
+}
+
+func (b *Bolt) GetByEmail(_ context.Context, email string) (models.User, error) {
+	var u models.User
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		u, err = boltGetByEmail(tx.Bucket(bucketUsers), email)
+		return err
+	})
+	return u, err
+}
+
+func (b *Bolt) List(_ context.Context, opts ListOptions) ([]models.User, error) {
+	var all []models.User
+	err := b.db.View(func(tx *bolt.Tx) error {
+		var err error
+		all, err = boltList(tx.Bucket(bucketUsers), opts)
+		return err
+	})
+	return all, err
+}
+
+// Each walks the bucket in key order, decoding one record at a time.
+func (b *Bolt) Each(_ context.Context, opts ListOptions, fn func(models.User) error) error {
+	return b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketUsers).ForEach(func(_, raw []byte) error {
+			var u models.User
+			if err := json.Unmarshal(raw, &u); err != nil {
+				return err
+			}
+			if u.DeletedAt != nil && !opts.IncludeDeleted {
+				return nil
+			}
+			if !opts.Matches(u) {
+				return nil
+			}
+			return fn(u)
+		})
+	})
+}
+
+func (b *Bolt) Create(_ context.Context, u models.User) (models.User, error) {
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		u, err = boltCreate(tx.Bucket(bucketUsers), u)
+		return err
+	})
+	if err != nil {
+		return models.User{}, fmt.Errorf("store: bolt create: %w", err)
+	}
+	return u, nil
+}
+
+func (b *Bolt) Update(_ context.Context, u models.User) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return boltUpdate(tx.Bucket(bucketUsers), u)
+	})
+}
+
+// Delete soft-deletes the record in place; Restore clears the marker.
+func (b *Bolt) Delete(_ context.Context, id int) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return boltSetDeleted(tx.Bucket(bucketUsers), id, true)
+	})
+}
+
+func (b *Bolt) Restore(_ context.Context, id int) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return boltSetDeleted(tx.Bucket(bucketUsers), id, false)
+	})
+}
+
+// Purge permanently removes records soft-deleted before cutoff.
+func (b *Bolt) Purge(_ context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	purged := 0
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(bucketUsers)
+		var stale [][]byte
+		err := bkt.ForEach(func(k, raw []byte) error {
+			var u models.User
+			if err := json.Unmarshal(raw, &u); err != nil {
+				return err
+			}
+			if u.DeletedAt != nil && u.DeletedAt.Before(cutoff) {
+				stale = append(stale, append([]byte(nil), k...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		purged = len(stale)
+		if dryRun {
+			return nil
+		}
+		for _, k := range stale {
+			if err := bkt.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("store: bolt purge: %w", err)
+	}
+	return purged, nil
+}
+
+// WithTx runs fn inside a single bolt update transaction; bbolt rolls
+// the whole transaction back when fn returns an error.
+func (b *Bolt) WithTx(_ context.Context, fn func(Store) error) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return fn(&boltTx{bkt: tx.Bucket(bucketUsers)})
+	})
+}
+
+// boltTx is the view handed to WithTx callbacks: every operation runs
+// against the already-open transaction.
+type boltTx struct {
+	bkt *bolt.Bucket
+}
+
+func (t *boltTx) Get(_ context.Context, id int) (models.User, error) {
+	return boltGet(t.bkt, id)
+}
+
+func (t *boltTx) GetByUUID(_ context.Context, uuid string) (models.User, error) {
+	return boltGetByUUID(t.bkt, uuid)
+}
+
+func (t *boltTx) GetByEmail(_ context.Context, email string) (models.User, error) {
+	return boltGetByEmail(t.bkt, email)
+}
+
+func (t *boltTx) List(_ context.Context, opts ListOptions) ([]models.User, error) {
+	return boltList(t.bkt, opts)
+}
+
+func (t *boltTx) Create(_ context.Context, u models.User) (models.User, error) {
+	return boltCreate(t.bkt, u)
+}
+
+func (t *boltTx) Update(_ context.Context, u models.User) error {
+	return boltUpdate(t.bkt, u)
+}
+
+func (t *boltTx) Delete(_ context.Context, id int) error {
+	return boltSetDeleted(t.bkt, id, true)
+}
+
+func (t *boltTx) Restore(_ context.Context, id int) error {
+	return boltSetDeleted(t.bkt, id, false)
+}
+
+func (t *boltTx) WithTx(_ context.Context, fn func(Store) error) error {
+	// Already inside a transaction; just run.
+	return fn(t)
+}
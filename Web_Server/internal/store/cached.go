@@ -0,0 +1,629 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/cache"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// WriteMode selects how Cached keeps the cache and the backing store
+// in step when a user is mutated.
+type WriteMode int
+
+const (
+	// WriteInvalidate drops the affected entry on every mutation and
+	// lets the next read refill it. Cheapest, and never caches a value
+	// the store rewrote on the way in.
+	WriteInvalidate WriteMode = iota
+
+	// WriteThrough writes the store first, then refreshes the entry
+	// from it, so a read right after a write is still a hit and sees
+	// exactly what the store kept.
+	WriteThrough
+
+	// WriteBehind acknowledges an update as soon as it is cached and
+	// queues the store write for a background worker. The queue is
+	// bounded — when it is full the update degrades to a synchronous
+	// write — and Close flushes whatever is still pending. A store-side
+	// failure surfaces in the log, not the response, so reads between
+	// the acknowledgement and the flush are eventually consistent.
+	WriteBehind
+)
+
+// writeBehindQueue bounds how many updates may sit unflushed; past
+// that, updates go to the store synchronously.
+const writeBehindQueue = 256
+
+// Cached decorates a Store with an in-memory read cache of single
+// users keyed by ID, so the hot lookup path stops paying the backend
+// round trip. Reads by UUID or email and collection queries pass
+// through untouched; the WriteMode decides what mutations do to the
+// cache, and a transaction flushes the whole cache since the decorator
+// cannot see what it touched. The TTL bounds how stale a read can get
+// when another process writes to the same backend behind this one.
+type Cached struct {
+	inner    Store
+	ids      *cache.Cache[int, models.User]
+	ttl      time.Duration
+	mode     WriteMode
+	capacity int
+
+	// misses remembers IDs the store said don't exist, so repeated
+	// lookups for the same missing user short-circuit. Nil when
+	// negative caching is off.
+	misses  *cache.Cache[int, struct{}]
+	missTTL time.Duration
+
+	// inv, when set, fans mutations out to the other instances'
+	// caches over pub/sub.
+	inv *Invalidator
+
+	// remote, when set, is a shared cache tier between the local one
+	// and the store: misses check it before paying the backend round
+	// trip, so a user one instance loaded is warm for all of them.
+	// codec is how users travel over the wire to it.
+	remote *cache.Distributed
+	codec  cache.Codec
+
+	// bloom, when set, answers "was this ID or email ever written?"
+	// ahead of every point read, so lookups for keys that never
+	// existed skip the backend outright. Swapped atomically because a
+	// transaction rebuilds it mid-flight.
+	bloom atomic.Pointer[cache.Bloom]
+
+	// lists, when set, caches whole List results keyed by their
+	// options, under a generation any mutation bumps. Nil when list
+	// caching is off.
+	lists *cache.Namespace[[]models.User]
+
+	// Write-behind state: queued updates, the count not yet applied,
+	// and the worker's exit signal. All nil/zero in the other modes.
+	queue   chan models.User
+	pending sync.WaitGroup
+	done    chan struct{}
+}
+
+// NewCached wraps inner with a cache of up to capacity users, each
+// entry living at most ttl, mutations handled per mode. The instance
+// registers itself as "users" with the cache metrics; WriteBehind
+// starts its flush worker here and stops it on Close.
+func NewCached(inner Store, capacity int, ttl time.Duration, mode WriteMode) *Cached {
+	ids := cache.New[int, models.User]()
+	if capacity > 0 {
+		ids.SetCapacity(capacity)
+	}
+	cache.Register("users", ids.Stats)
+	cache.RegisterShards("users", ids.ShardStats)
+	cache.RegisterLoads("users", ids.LoadStats)
+	cache.RegisterTTL("users", func(key string) (time.Duration, bool) {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return 0, false
+		}
+		return ids.TTL(id)
+	})
+	cache.RegisterAdmin("users", cache.NewAdmin(ids, strconv.Atoi, strconv.Itoa))
+	c := &Cached{inner: inner, ids: ids, ttl: ttl, mode: mode, capacity: capacity, codec: cache.JSONCodec}
+	if mode == WriteBehind {
+		c.queue = make(chan models.User, writeBehindQueue)
+		c.done = make(chan struct{})
+		go c.flusher()
+	}
+	return c
+}
+
+// flusher applies queued write-behind updates in order until the
+// queue closes. A failed flush is logged and the cached entry dropped,
+// so reads stop repeating a write the store refused.
+func (c *Cached) flusher() {
+	defer close(c.done)
+	for u := range c.queue {
+		if err := c.inner.Update(context.Background(), u); err != nil {
+			log.Printf("store: write-behind update for user %d: %v", u.ID, err)
+			c.ids.Delete(u.ID)
+		} else {
+			c.remoteDrop(context.Background(), u.ID)
+			c.broadcast(context.Background(), u.ID)
+		}
+		c.pending.Done()
+	}
+}
+
+// SetNegativeTTL turns on negative caching: a Get the store answered
+// with ErrNotFound is remembered for ttl, and repeats inside that
+// window never reach the backend. The TTL should be short — a user
+// created elsewhere stays invisible here for at most that long. The
+// miss cache registers as "user_misses" with the cache metrics.
+func (c *Cached) SetNegativeTTL(ttl time.Duration) {
+	misses := cache.New[int, struct{}]()
+	if c.capacity > 0 {
+		misses.SetCapacity(c.capacity)
+	}
+	cache.Register("user_misses", misses.Stats)
+	cache.RegisterShards("user_misses", misses.ShardStats)
+	cache.RegisterTTL("user_misses", func(key string) (time.Duration, bool) {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return 0, false
+		}
+		return misses.TTL(id)
+	})
+	cache.RegisterAdmin("user_misses", cache.NewAdmin(misses, strconv.Atoi, strconv.Itoa))
+	c.misses = misses
+	c.missTTL = ttl
+}
+
+// SetListCache caches up to capacity whole List results. Any
+// mutation bumps the namespace generation, invalidating every cached
+// list in O(1) — after a bulk import there is no telling which query
+// results changed, and scanning keys to find out would cost more than
+// the cache saves. Callers must treat returned slices as read-only.
+// The cache registers as "user_lists" with the metrics. Call before
+// the cache sees traffic.
+func (c *Cached) SetListCache(capacity int) {
+	lists := cache.New[string, []models.User]()
+	if capacity > 0 {
+		lists.SetCapacity(capacity)
+	}
+	cache.Register("user_lists", lists.Stats)
+	cache.RegisterShards("user_lists", lists.ShardStats)
+	c.lists = cache.NewNamespace(lists, "lists")
+}
+
+// bumpLists retires every cached list result.
+func (c *Cached) bumpLists() {
+	if c.lists != nil {
+		c.lists.Bump()
+	}
+}
+
+// listKey renders the options into a stable cache key.
+func listKey(opts ListOptions) string {
+	return fmt.Sprintf("%+v", opts)
+}
+
+// SetRemote adds a distributed middle tier: local miss, then remote,
+// then the store, with fills flowing back down. Remote traffic is
+// best-effort — a dead cache node costs latency, never correctness.
+// Call before the cache sees traffic; Close tears it down.
+func (c *Cached) SetRemote(d *cache.Distributed) {
+	c.remote = d
+}
+
+// SetCodec picks how users are serialized for the remote tier; the
+// default is JSON. Every instance sharing the tier must agree on it.
+// Call before the cache sees traffic.
+func (c *Cached) SetCodec(codec cache.Codec) {
+	c.codec = codec
+}
+
+// remoteKey names a user's entry on the shared tier.
+func remoteKey(id int) string { return "iris:users:" + strconv.Itoa(id) }
+
+// remoteGet looks a user up on the shared tier.
+func (c *Cached) remoteGet(ctx context.Context, id int) (models.User, bool) {
+	if c.remote == nil {
+		return models.User{}, false
+	}
+	raw, ok, err := c.remote.Get(ctx, remoteKey(id))
+	if err != nil {
+		log.Printf("store: remote cache get user %d: %v", id, err)
+		return models.User{}, false
+	}
+	if !ok {
+		return models.User{}, false
+	}
+	var u models.User
+	if err := c.codec.Unmarshal(raw, &u); err != nil {
+		log.Printf("store: remote cache decode user %d: %v", id, err)
+		return models.User{}, false
+	}
+	return u, true
+}
+
+// remoteStore writes a user to the shared tier, best-effort.
+func (c *Cached) remoteStore(ctx context.Context, u models.User) {
+	if c.remote == nil {
+		return
+	}
+	raw, err := c.codec.Marshal(u)
+	if err != nil {
+		return
+	}
+	if err := c.remote.Set(ctx, remoteKey(u.ID), raw, c.ttl); err != nil {
+		log.Printf("store: remote cache set user %d: %v", u.ID, err)
+	}
+}
+
+// remoteDrop removes a user from the shared tier, best-effort.
+func (c *Cached) remoteDrop(ctx context.Context, id int) {
+	if c.remote == nil {
+		return
+	}
+	if err := c.remote.Delete(ctx, remoteKey(id)); err != nil {
+		log.Printf("store: remote cache delete user %d: %v", id, err)
+	}
+}
+
+// SetInvalidator joins this cache to the shared invalidation channel:
+// local mutations are published once they have reached the backend,
+// and remote ones evict the local entry — positive and negative both,
+// since a create elsewhere also outdates a cached "not found". Call
+// before the cache sees traffic; Close tears it down.
+func (c *Cached) SetInvalidator(inv *Invalidator) {
+	c.inv = inv
+	inv.listen(func(id int) {
+		// Any remote mutation may have changed list results.
+		c.bumpLists()
+		if id == flushAll {
+			c.ids.Clear()
+			if c.misses != nil {
+				c.misses.Clear()
+			}
+			// A remote transaction may have created users this filter
+			// has never seen; rebuild or retire it.
+			if c.bloom.Load() != nil {
+				if err := c.rebuildBloom(context.Background()); err != nil {
+					log.Printf("store: rebuilding user bloom filter: %v", err)
+					c.bloom.Store(nil)
+				}
+			}
+			return
+		}
+		c.ids.Delete(id)
+		c.forget(id)
+		// A create elsewhere must also reach the filter, or this
+		// instance would swear the new user doesn't exist. The payload
+		// carries no email; see EnableBloom for that caveat.
+		c.bloomAdd(bloomID(id))
+	})
+}
+
+// broadcast publishes an invalidation when an invalidator is attached.
+// Callers must only do so after the mutation has reached the shared
+// backend — evicting remote caches before the write lands would just
+// refill them with the old row.
+func (c *Cached) broadcast(ctx context.Context, id int) {
+	if c.inv != nil {
+		c.inv.publish(ctx, id)
+	}
+}
+
+// SetSliding switches the ID cache to sliding expiration, so a user
+// read at least once per TTL never goes cold. It also unbounds how
+// stale a hot entry may get behind an external writer, which is why it
+// is opt-in.
+func (c *Cached) SetSliding(on bool) {
+	c.ids.SetSliding(on)
+}
+
+// SetStaleWindow turns on stale-while-revalidate for ID reads: a hit
+// inside window past the entry's TTL is served as-is while the entry
+// refreshes from the store in the background. Tail latency on hot
+// users stays flat through expiry, for at most window of extra
+// staleness.
+func (c *Cached) SetStaleWindow(window time.Duration) {
+	c.ids.SetStaleWhileRevalidate(window)
+}
+
+// Warm preloads the n most recently created users, so the first
+// requests after a restart find a hot cache. The caller's context
+// bounds how long startup may spend on it.
+func (c *Cached) Warm(ctx context.Context, n int) (int, error) {
+	all, err := c.inner.List(ctx, ListOptions{Sort: []SortKey{{Field: "id", Desc: true}}})
+	if err != nil {
+		return 0, err
+	}
+	if len(all) > n {
+		all = all[:n]
+	}
+	for _, u := range all {
+		c.ids.Set(u.ID, u, c.ttl)
+	}
+	return len(all), nil
+}
+
+// StartJanitor forwards to the underlying caches' expiry sweepers.
+func (c *Cached) StartJanitor(ctx context.Context, interval time.Duration) {
+	c.ids.StartJanitor(ctx, interval)
+	if c.misses != nil {
+		c.misses.StartJanitor(ctx, interval)
+	}
+}
+
+// forget drops any negative entry for id, typically because the user
+// now demonstrably exists.
+func (c *Cached) forget(id int) {
+	if c.misses != nil {
+		c.misses.Delete(id)
+	}
+}
+
+// bloomID and bloomEmail namespace the two key kinds sharing one
+// filter, so an email that happens to spell an integer cannot vouch
+// for that ID.
+func bloomID(id int) string          { return "id:" + strconv.Itoa(id) }
+func bloomEmail(email string) string { return "email:" + email }
+
+// EnableBloom fronts point reads with a membership filter: a Get or
+// GetByEmail for a key the filter has never seen returns ErrNotFound
+// without touching any tier, which is the whole cost of a miss-heavy
+// workload. The filter is built here from every user the store knows
+// (deleted included — their keys exist) and maintained on writes; a
+// transaction rebuilds it, since the decorator cannot see what one
+// touched. It assumes every write flows through this process: the
+// invalidation channel patches in IDs created elsewhere, but not
+// their emails, so leave this off when other writers share the
+// backend and clients read by email.
+func (c *Cached) EnableBloom(ctx context.Context) error {
+	return c.rebuildBloom(ctx)
+}
+
+// rebuildBloom constructs a fresh filter from the store and swaps it
+// in, sized with headroom for growth past the current population.
+func (c *Cached) rebuildBloom(ctx context.Context) error {
+	all, err := c.inner.List(ctx, ListOptions{IncludeDeleted: true})
+	if err != nil {
+		return err
+	}
+	b := cache.NewBloom(2*len(all) + 4096)
+	for _, u := range all {
+		b.Add(bloomID(u.ID))
+		b.Add(bloomEmail(u.Email))
+	}
+	c.bloom.Store(b)
+	return nil
+}
+
+// bloomAdd marks keys as written on the active filter, if any.
+func (c *Cached) bloomAdd(keys ...string) {
+	b := c.bloom.Load()
+	if b == nil {
+		return
+	}
+	for _, k := range keys {
+		b.Add(k)
+	}
+}
+
+// Get is read-through with a single flight per ID: concurrent misses
+// for one user share one backend round trip, and the loader walks the
+// tiers — negative cache, remote, store — filling them on the way
+// back out.
+func (c *Cached) Get(ctx context.Context, id int) (models.User, error) {
+	if b := c.bloom.Load(); b != nil && !b.MayContain(bloomID(id)) {
+		return models.User{}, ErrNotFound
+	}
+	return c.ids.GetOrLoad(ctx, id, c.ttl, func(ctx context.Context) (models.User, error) {
+		if c.misses != nil {
+			if _, ok := c.misses.Get(id); ok {
+				return models.User{}, ErrNotFound
+			}
+		}
+		if u, ok := c.remoteGet(ctx, id); ok {
+			return u, nil
+		}
+		u, err := c.inner.Get(ctx, id)
+		switch {
+		case err == nil:
+			c.remoteStore(ctx, u)
+		case errors.Is(err, ErrNotFound) && c.misses != nil:
+			c.misses.Set(id, struct{}{}, c.missTTL)
+		}
+		return u, err
+	})
+}
+
+func (c *Cached) GetByUUID(ctx context.Context, uuid string) (models.User, error) {
+	return c.inner.GetByUUID(ctx, uuid)
+}
+
+func (c *Cached) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	if b := c.bloom.Load(); b != nil && !b.MayContain(bloomEmail(email)) {
+		return models.User{}, ErrNotFound
+	}
+	return c.inner.GetByEmail(ctx, email)
+}
+
+func (c *Cached) List(ctx context.Context, opts ListOptions) ([]models.User, error) {
+	if c.lists == nil {
+		return c.inner.List(ctx, opts)
+	}
+	key := listKey(opts)
+	if us, ok := c.lists.Get(key); ok {
+		return us, nil
+	}
+	us, err := c.inner.List(ctx, opts)
+	if err == nil {
+		c.lists.Set(key, us, c.ttl)
+	}
+	return us, err
+}
+
+func (c *Cached) Create(ctx context.Context, u models.User) (models.User, error) {
+	created, err := c.inner.Create(ctx, u)
+	if err == nil {
+		c.ids.Set(created.ID, created, c.ttl)
+		c.forget(created.ID)
+		c.bloomAdd(bloomID(created.ID), bloomEmail(created.Email))
+		c.bumpLists()
+		c.remoteStore(ctx, created)
+		c.broadcast(ctx, created.ID)
+	}
+	return created, err
+}
+
+func (c *Cached) Update(ctx context.Context, u models.User) error {
+	if c.mode == WriteBehind {
+		return c.updateBehind(ctx, u)
+	}
+	if err := c.inner.Update(ctx, u); err != nil {
+		return err
+	}
+	c.forget(u.ID)
+	// The update may have re-addressed the user; the old email stays
+	// in the filter, which is safe, just not free.
+	c.bloomAdd(bloomEmail(u.Email))
+	c.bumpLists()
+	c.remoteDrop(ctx, u.ID)
+	c.broadcast(ctx, u.ID)
+	if c.mode == WriteThrough {
+		// Refresh from the store rather than caching u: the store
+		// rewrites fields on the way in (immutable UUID, the password
+		// hash), and the cache must hold what it actually kept.
+		if fresh, err := c.inner.Get(ctx, u.ID); err == nil {
+			c.ids.Set(u.ID, fresh, c.ttl)
+			c.remoteStore(ctx, fresh)
+			return nil
+		}
+	}
+	c.ids.Delete(u.ID)
+	return nil
+}
+
+// updateBehind caches the new value and hands the store write to the
+// flusher. Creates stay synchronous even in this mode — the store
+// assigns the ID — as do deletes and restores, which are rare and not
+// worth acknowledging before they hold.
+func (c *Cached) updateBehind(ctx context.Context, u models.User) error {
+	if old, ok := c.ids.Get(u.ID); ok {
+		// Carry over the fields the store would preserve, so the
+		// cached value matches what the flush will persist.
+		u.UUID = old.UUID
+		u.Password = ""
+		u.PasswordHash = old.PasswordHash
+		c.ids.Set(u.ID, u, c.ttl)
+	}
+	c.bloomAdd(bloomEmail(u.Email))
+	c.bumpLists()
+	c.pending.Add(1)
+	select {
+	case c.queue <- u:
+		return nil
+	default:
+		c.pending.Done()
+	}
+	// Queue full: degrade to a synchronous write rather than block
+	// the request or drop the mutation.
+	err := c.inner.Update(ctx, u)
+	if err != nil {
+		c.ids.Delete(u.ID)
+		return err
+	}
+	c.remoteDrop(ctx, u.ID)
+	c.broadcast(ctx, u.ID)
+	return nil
+}
+
+func (c *Cached) Delete(ctx context.Context, id int) error {
+	err := c.inner.Delete(ctx, id)
+	if err == nil {
+		c.ids.Delete(id)
+		c.bumpLists()
+		c.remoteDrop(ctx, id)
+		c.broadcast(ctx, id)
+	}
+	return err
+}
+
+func (c *Cached) Restore(ctx context.Context, id int) error {
+	err := c.inner.Restore(ctx, id)
+	if err == nil {
+		c.ids.Delete(id)
+		// A soft-deleted user reads as not found, so the restore must
+		// clear any negative entry it earned while deleted.
+		c.forget(id)
+		c.bumpLists()
+		c.remoteDrop(ctx, id)
+		c.broadcast(ctx, id)
+	}
+	return err
+}
+
+// WithTx waits for any write-behind backlog — the transaction must
+// see every acknowledged update — then runs fn directly against the
+// inner store, since caching inside a transaction would serve reads
+// the transaction itself may be changing, and flushes the cache once
+// the transaction commits.
+func (c *Cached) WithTx(ctx context.Context, fn func(Store) error) error {
+	c.pending.Wait()
+	err := c.inner.WithTx(ctx, fn)
+	if err == nil {
+		c.ids.Clear()
+		if c.misses != nil {
+			c.misses.Clear()
+		}
+		c.bumpLists()
+		if c.bloom.Load() != nil {
+			if rerr := c.rebuildBloom(ctx); rerr != nil {
+				// Serving without the filter beats serving wrong
+				// not-founds from a stale one.
+				log.Printf("store: rebuilding user bloom filter: %v", rerr)
+				c.bloom.Store(nil)
+			}
+		}
+		c.broadcast(ctx, flushAll)
+	}
+	return err
+}
+
+// Each streams through the inner store's Iterator when it has one.
+func (c *Cached) Each(ctx context.Context, opts ListOptions, fn func(models.User) error) error {
+	return Each(ctx, c.inner, opts, fn)
+}
+
+// Search uses the inner store's native Searcher when available, the
+// in-process scan otherwise.
+func (c *Cached) Search(ctx context.Context, query string, limit int) ([]models.User, error) {
+	return SearchUsers(ctx, c.inner, query, limit)
+}
+
+// Stats aggregates natively when the inner store can.
+func (c *Cached) Stats(ctx context.Context) (Stats, error) {
+	return UserStats(ctx, c.inner)
+}
+
+// Purge forwards to the inner store's Purger and flushes the cache,
+// since purged users may still be cached as soft-deleted.
+func (c *Cached) Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	p, ok := c.inner.(Purger)
+	if !ok {
+		return 0, errors.New("store: inner store does not support purge")
+	}
+	n, err := p.Purge(ctx, cutoff, dryRun)
+	if err == nil && !dryRun && n > 0 {
+		c.ids.Clear()
+		c.bumpLists()
+		c.broadcast(ctx, flushAll)
+	}
+	return n, err
+}
+
+// Close drains the write-behind queue — every acknowledged update
+// reaches the store before shutdown — and then forwards to the inner
+// store when it holds resources.
+func (c *Cached) Close() error {
+	if c.queue != nil {
+		close(c.queue)
+		<-c.done
+	}
+	if c.inv != nil {
+		c.inv.Close()
+	}
+	if c.remote != nil {
+		c.remote.Close()
+	}
+	if cl, ok := c.inner.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
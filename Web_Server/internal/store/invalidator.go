@@ -0,0 +1,81 @@
+package store
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationChannel is the Redis pub/sub channel replica caches
+// meet on.
+const invalidationChannel = "iris:cache:users"
+
+// flushAll is the ID broadcast for mutations whose reach is unknown
+// (transactions, purges): every subscriber drops its whole cache.
+const flushAll = -1
+
+// Invalidator spreads user-cache invalidations across instances that
+// share one backend: each mutation publishes the affected ID, every
+// instance subscribes and evicts locally, so a write on one replica
+// stops the others serving the stale entry for a full TTL. Messages
+// carry the sender's instance ID, letting a replica skip its own —
+// it already evicted synchronously.
+type Invalidator struct {
+	client *redis.Client
+	sub    *redis.PubSub
+	self   string
+}
+
+// NewInvalidator connects to the Redis at addr, failing fast when it
+// is unreachable like the other Redis-backed pieces do.
+func NewInvalidator(ctx context.Context, addr string) (*Invalidator, error) {
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return &Invalidator{client: client, self: NewUUID()}, nil
+}
+
+// publish broadcasts id best-effort. A replica that misses a message
+// serves stale reads only until the entry's TTL runs out, so a Redis
+// hiccup is logged rather than failed up to the request that wrote.
+func (i *Invalidator) publish(ctx context.Context, id int) {
+	msg := i.self + ":" + strconv.Itoa(id)
+	if err := i.client.Publish(ctx, invalidationChannel, msg).Err(); err != nil {
+		log.Printf("store: publish invalidation for user %d: %v", id, err)
+	}
+}
+
+// listen delivers remote invalidations to evict — an ID, or flushAll
+// — until Close. Malformed payloads are dropped; the channel is
+// shared infrastructure and a bad citizen on it should not crash
+// everyone else.
+func (i *Invalidator) listen(evict func(int)) {
+	i.sub = i.client.Subscribe(context.Background(), invalidationChannel)
+	go func() {
+		for msg := range i.sub.Channel() {
+			origin, rest, ok := strings.Cut(msg.Payload, ":")
+			if !ok || origin == i.self {
+				continue
+			}
+			id, err := strconv.Atoi(rest)
+			if err != nil {
+				continue
+			}
+			evict(id)
+		}
+	}()
+}
+
+// Close leaves the channel and drops the connection; the subscription
+// goroutine ends when its channel drains.
+func (i *Invalidator) Close() error {
+	if i.sub != nil {
+		i.sub.Close()
+	}
+	return i.client.Close()
+}
@@ -0,0 +1,477 @@
+package store
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// Memory is the default Store: mutations go through a write lock,
+// while reads resolve against an immutable copy-on-write snapshot
+// with a single atomic load — no reader ever touches the mutex, so
+// read traffic cannot serialize behind it. It is volatile — contents
+// are lost on restart.
+type Memory struct {
+	mu     sync.RWMutex
+	users  map[int]models.User
+	byUUID map[string]int
+
+	// byEmail indexes live (non-deleted) users by email, maintained
+	// under the same lock as the primary map.
+	byEmail map[string]int
+
+	// view is the read snapshot, republished after every mutation.
+	// The maps it holds are never written again once published. A nil
+	// view falls back to the locked path.
+	view atomic.Pointer[memView]
+
+	// inTx suppresses snapshot publishing while WithTx runs, so
+	// readers never observe a half-applied transaction; the final
+	// publish happens when it ends.
+	inTx bool
+
+	// nextID is the ID generator. It is seeded past every ID seen in
+	// a snapshot or operation log, so restarts never reuse an ID even
+	// after the highest-numbered user has been deleted.
+	nextID atomic.Int64
+
+	// Snapshot persistence, configured by NewMemoryWithPersistence.
+	snapshotPath string
+	stopSnapshot chan struct{}
+	snapshotDone chan struct{}
+
+	// fsync forces snapshot writes (and WAL appends) to stable
+	// storage before they count as done.
+	fsync bool
+
+	// changed receives a token after each mutation when the OnChange
+	// persistence mode is active; nil otherwise.
+	changed chan struct{}
+
+	// searchIndex maps trigrams of live users' names and emails to
+	// user IDs. It is built lazily on the first Search and discarded
+	// wholesale on mutation rather than maintained incrementally.
+	searchIndex map[string][]int
+
+	// Operation log, configured by NewMemoryWithWAL.
+	wal *wal
+}
+
+// NewMemory returns an empty in-memory store.
+func NewMemory() *Memory {
+	m := &Memory{
+		users:   make(map[int]models.User),
+		byUUID:  make(map[string]int),
+		byEmail: make(map[string]int),
+	}
+	m.publishLocked()
+	return m
+}
+
+// memView is one immutable generation of the index maps. Readers
+// share it freely; writers build a fresh one rather than touch it.
+type memView struct {
+	users   map[int]models.User
+	byUUID  map[string]int
+	byEmail map[string]int
+}
+
+// publishLocked rebuilds the read snapshot from the canonical maps;
+// the caller holds the write lock (or is still inside a constructor).
+// Copying is O(n) per mutation — the price of lock-free reads, and
+// the right trade for this store's read-heavy traffic.
+func (m *Memory) publishLocked() {
+	if m.inTx {
+		return
+	}
+	v := &memView{
+		users:   make(map[int]models.User, len(m.users)),
+		byUUID:  make(map[string]int, len(m.byUUID)),
+		byEmail: make(map[string]int, len(m.byEmail)),
+	}
+	maps.Copy(v.users, m.users)
+	maps.Copy(v.byUUID, m.byUUID)
+	maps.Copy(v.byEmail, m.byEmail)
+	m.view.Store(v)
+}
+
+// The *Locked variants assume the appropriate lock is already held;
+// the exported methods and WithTx take it.
+
+// The *From helpers run against any generation of the maps — the
+// canonical ones under the write lock, or a published snapshot.
+
+func getFrom(users map[int]models.User, id int) (models.User, error) {
+	u, ok := users[id]
+	if !ok || u.DeletedAt != nil {
+		return models.User{}, ErrNotFound
+	}
+	return u, nil
+}
+
+func listFrom(users map[int]models.User, opts ListOptions) []models.User {
+	all := make([]models.User, 0, len(users))
+	for _, u := range users {
+		if u.DeletedAt != nil && !opts.IncludeDeleted {
+			continue
+		}
+		if !opts.Matches(u) {
+			continue
+		}
+		all = append(all, u)
+	}
+	SortUsers(all, opts.Sort)
+	return all
+}
+
+func (m *Memory) getLocked(id int) (models.User, error) {
+	return getFrom(m.users, id)
+}
+
+func (m *Memory) getByUUIDLocked(uuid string) (models.User, error) {
+	id, ok := m.byUUID[uuid]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return m.getLocked(id)
+}
+
+func (m *Memory) getByEmailLocked(email string) (models.User, error) {
+	id, ok := m.byEmail[email]
+	if !ok {
+		return models.User{}, ErrNotFound
+	}
+	return m.getLocked(id)
+}
+
+func (m *Memory) listLocked(opts ListOptions) []models.User {
+	return listFrom(m.users, opts)
+}
+
+func (m *Memory) createLocked(u models.User) (models.User, error) {
+	// A live holder of the email blocks the create; without this the
+	// index would silently re-point at the newcomer and orphan the
+	// original account.
+	if _, taken := m.byEmail[u.Email]; taken {
+		return models.User{}, ErrConflict
+	}
+	// IDs are always assigned server-side; anything the client sent
+	// is ignored.
+	u.ID = int(m.nextID.Add(1))
+	u.UUID = NewUUID()
+	u.Password = ""
+	u.DeletedAt = nil
+	m.users[u.ID] = u
+	m.byUUID[u.UUID] = u.ID
+	m.byEmail[u.Email] = u.ID
+	if m.wal != nil {
+		m.wal.append(walEntry{Op: "create", User: u})
+	}
+	m.noteChange()
+	return u, nil
+}
+
+func (m *Memory) updateLocked(u models.User) error {
+	old, ok := m.users[u.ID]
+	if !ok || old.DeletedAt != nil {
+		return ErrNotFound
+	}
+	// A changed email must not steal an address another live user
+	// holds.
+	if old.Email != u.Email {
+		if holder, taken := m.byEmail[u.Email]; taken && holder != u.ID {
+			return ErrConflict
+		}
+	}
+	// The UUID is immutable once assigned; the password hash only
+	// changes through the auth flows, not plain updates.
+	u.UUID = old.UUID
+	u.Password = ""
+	u.PasswordHash = old.PasswordHash
+	if old.Email != u.Email {
+		delete(m.byEmail, old.Email)
+	}
+	m.byEmail[u.Email] = u.ID
+	m.users[u.ID] = u
+	if m.wal != nil {
+		m.wal.append(walEntry{Op: "update", User: u})
+	}
+	m.noteChange()
+	return nil
+}
+
+func (m *Memory) deleteLocked(id int) error {
+	u, ok := m.users[id]
+	if !ok || u.DeletedAt != nil {
+		return ErrNotFound
+	}
+	now := time.Now().UTC()
+	u.DeletedAt = &now
+	m.users[id] = u
+	// Deleted users release their email so a new account can claim it.
+	delete(m.byEmail, u.Email)
+	if m.wal != nil {
+		m.wal.append(walEntry{Op: "update", User: u})
+	}
+	m.noteChange()
+	return nil
+}
+
+func (m *Memory) restoreLocked(id int) error {
+	u, ok := m.users[id]
+	if !ok || u.DeletedAt == nil {
+		return ErrNotFound
+	}
+	// The address may have been re-registered while this user was
+	// deleted; coming back cannot evict the live holder.
+	if holder, taken := m.byEmail[u.Email]; taken && holder != id {
+		return ErrConflict
+	}
+	u.DeletedAt = nil
+	m.users[id] = u
+	m.byEmail[u.Email] = id
+	if m.wal != nil {
+		m.wal.append(walEntry{Op: "update", User: u})
+	}
+	m.noteChange()
+	return nil
+}
+
+func (m *Memory) Get(_ context.Context, id int) (models.User, error) {
+	if v := m.view.Load(); v != nil {
+		return getFrom(v.users, id)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getLocked(id)
+}
+
+func (m *Memory) GetByUUID(_ context.Context, uuid string) (models.User, error) {
+	if v := m.view.Load(); v != nil {
+		id, ok := v.byUUID[uuid]
+		if !ok {
+			return models.User{}, ErrNotFound
+		}
+		return getFrom(v.users, id)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getByUUIDLocked(uuid)
+}
+
+func (m *Memory) GetByEmail(_ context.Context, email string) (models.User, error) {
+	if v := m.view.Load(); v != nil {
+		id, ok := v.byEmail[email]
+		if !ok {
+			return models.User{}, ErrNotFound
+		}
+		return getFrom(v.users, id)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.getByEmailLocked(email)
+}
+
+func (m *Memory) List(_ context.Context, opts ListOptions) ([]models.User, error) {
+	if v := m.view.Load(); v != nil {
+		return listFrom(v.users, opts), nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.listLocked(opts), nil
+}
+
+func (m *Memory) Create(_ context.Context, u models.User) (models.User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.createLocked(u)
+}
+
+func (m *Memory) Update(_ context.Context, u models.User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateLocked(u)
+}
+
+// Delete soft-deletes: the record is kept (and restorable) until a
+// purge removes it for good.
+func (m *Memory) Delete(_ context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.deleteLocked(id)
+}
+
+func (m *Memory) Restore(_ context.Context, id int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.restoreLocked(id)
+}
+
+// Search implements Searcher over the lazily built trigram index:
+// the query's trigrams nominate candidate users, which are then
+// scored and ordered like the generic scan would.
+func (m *Memory) Search(_ context.Context, query string, limit int) ([]models.User, error) {
+	m.mu.Lock()
+	if m.searchIndex == nil {
+		m.searchIndex = map[string][]int{}
+		for id, u := range m.users {
+			if u.DeletedAt != nil {
+				continue
+			}
+			for t := range trigrams(u.Name + " " + u.Email) {
+				m.searchIndex[t] = append(m.searchIndex[t], id)
+			}
+		}
+	}
+	candidates := map[int]struct{}{}
+	for t := range trigrams(query) {
+		for _, id := range m.searchIndex[t] {
+			candidates[id] = struct{}{}
+		}
+	}
+	type scored struct {
+		u     models.User
+		score float64
+	}
+	var matches []scored
+	for id := range candidates {
+		u, err := m.getLocked(id)
+		if err != nil {
+			continue
+		}
+		if score := matchScore(u, query); score >= searchThreshold {
+			matches = append(matches, scored{u: u, score: score})
+		}
+	}
+	m.mu.Unlock()
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].u.ID < matches[j].u.ID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	out := make([]models.User, len(matches))
+	for i, s := range matches {
+		out[i] = s.u
+	}
+	return out, nil
+}
+
+// WithTx emulates a transaction by holding the write lock for the
+// duration of fn, so the grouped operations are observed atomically.
+// Unlike the SQL backends there is no rollback: mutations made before
+// an error are kept.
+func (m *Memory) WithTx(_ context.Context, fn func(Store) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inTx = true
+	err := fn(&memoryTx{m: m})
+	m.inTx = false
+	m.publishLocked()
+	return err
+}
+
+// memoryTx is the view handed to WithTx callbacks: same store, no
+// locking, since the caller already holds the write lock.
+type memoryTx struct {
+	m *Memory
+}
+
+func (t *memoryTx) Get(_ context.Context, id int) (models.User, error) {
+	return t.m.getLocked(id)
+}
+
+func (t *memoryTx) GetByUUID(_ context.Context, uuid string) (models.User, error) {
+	return t.m.getByUUIDLocked(uuid)
+}
+
+func (t *memoryTx) GetByEmail(_ context.Context, email string) (models.User, error) {
+	return t.m.getByEmailLocked(email)
+}
+
+func (t *memoryTx) List(_ context.Context, opts ListOptions) ([]models.User, error) {
+	return t.m.listLocked(opts), nil
+}
+
+func (t *memoryTx) Create(_ context.Context, u models.User) (models.User, error) {
+	return t.m.createLocked(u)
+}
+
+func (t *memoryTx) Update(_ context.Context, u models.User) error {
+	return t.m.updateLocked(u)
+}
+
+func (t *memoryTx) Delete(_ context.Context, id int) error {
+	return t.m.deleteLocked(id)
+}
+
+func (t *memoryTx) Restore(_ context.Context, id int) error {
+	return t.m.restoreLocked(id)
+}
+
+func (t *memoryTx) WithTx(_ context.Context, fn func(Store) error) error {
+	// Already inside the lock; just run.
+	return fn(t)
+}
+
+// Purge permanently removes users soft-deleted before cutoff.
+func (m *Memory) Purge(_ context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	purged := 0
+	for id, u := range m.users {
+		if u.DeletedAt == nil || !u.DeletedAt.Before(cutoff) {
+			continue
+		}
+		purged++
+		if dryRun {
+			continue
+		}
+		delete(m.users, id)
+		delete(m.byUUID, u.UUID)
+		if m.wal != nil {
+			m.wal.append(walEntry{Op: "delete", User: models.User{ID: id}})
+		}
+	}
+	if purged > 0 && !dryRun {
+		m.noteChange()
+	}
+	return purged, nil
+}
+
+// noteChange signals the OnChange persistence loop, if active, that a
+// mutation happened. Non-blocking: one pending token is enough.
+func (m *Memory) noteChange() {
+	// Any mutation invalidates the lazily built search index and
+	// outdates the read snapshot; the caller already holds the write
+	// lock.
+	m.searchIndex = nil
+	m.publishLocked()
+	if m.changed == nil {
+		return
+	}
+	select {
+	case m.changed <- struct{}{}:
+	default:
+	}
+}
+
+// bumpNextID advances the ID generator past id; used when restoring
+// persisted state.
+func (m *Memory) bumpNextID(id int) {
+	for {
+		cur := m.nextID.Load()
+		if int64(id) <= cur || m.nextID.CompareAndSwap(cur, int64(id)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// mongoOpTimeout bounds every single operation so a slow or
+// partitioned cluster cannot hang a request indefinitely.
+const mongoOpTimeout = 5 * time.Second
+
+// Mongo is a Store backed by a MongoDB collection. Documents use the
+// integer user ID as _id; IDs are allocated from a counters collection
+// so they stay dense and monotonic like the SQL backends'.
+type Mongo struct {
+	client   *mongo.Client
+	users    *mongo.Collection
+	counters *mongo.Collection
+
+	// sc, when set, is the session context WithTx operations run
+	// under; it overrides the per-call context the same way the SQL
+	// backends swap in their transaction handle.
+	sc mongo.SessionContext
+}
+
+// NewMongo connects to the deployment at uri and prepares the "users"
+// collection in the given database, creating the email index.
+func NewMongo(ctx context.Context, uri, database string) (*Mongo, error) {
+	ctx, cancel := context.WithTimeout(ctx, mongoOpTimeout)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		return nil, fmt.Errorf("store: connecting to mongo: %w", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("store: pinging mongo: %w", err)
+	}
+	db := client.Database(database)
+	m := &Mongo{
+		client:   client,
+		users:    db.Collection("users"),
+		counters: db.Collection("counters"),
+	}
+	// Only live users must be unique by email; soft-deleted ones keep
+	// their address without blocking re-registration.
+	_, err = m.users.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true).SetPartialFilterExpression(
+			bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: false}}}}),
+	})
+	if err != nil {
+		client.Disconnect(context.Background())
+		return nil, fmt.Errorf("store: creating mongo email index: %w", err)
+	}
+	return m, nil
+}
+
+// Close disconnects from the deployment.
+func (m *Mongo) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), mongoOpTimeout)
+	defer cancel()
+	return m.client.Disconnect(ctx)
+}
+
+// opCtx applies the per-operation deadline, preferring the session
+// context inside WithTx.
+func (m *Mongo) opCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.sc != nil {
+		ctx = m.sc
+	}
+	return context.WithTimeout(ctx, mongoOpTimeout)
+}
+
+func mongoErr(op string, err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return fmt.Errorf("store: mongo %s: %w", op, ErrConflict)
+	}
+	return fmt.Errorf("store: mongo %s: %w", op, err)
+}
+
+// nextID atomically increments and returns the user ID counter.
+func (m *Mongo) nextID(ctx context.Context) (int, error) {
+	var doc struct {
+		Seq int `bson:"seq"`
+	}
+	err := m.counters.FindOneAndUpdate(ctx,
+		bson.D{{Key: "_id", Value: "users"}},
+		bson.D{{Key: "$inc", Value: bson.D{{Key: "seq", Value: 1}}}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&doc)
+	if err != nil {
+		return 0, err
+	}
+	return doc.Seq, nil
+}
+
+// notDeleted filters out soft-deleted documents.
+var notDeleted = bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: false}}}}
+
+func (m *Mongo) findOne(ctx context.Context, filter bson.D) (models.User, error) {
+	ctx, cancel := m.opCtx(ctx)
+	defer cancel()
+	var u models.User
+	err := m.users.FindOne(ctx, append(filter, notDeleted...)).Decode(&u)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return models.User{}, ErrNotFound
+	}
+	if err != nil {
+		return models.User{}, mongoErr("get", err)
+	}
+	return u, nil
+}
+
+func (m *Mongo) Get(ctx context.Context, id int) (models.User, error) {
+	return m.findOne(ctx, bson.D{{Key: "_id", Value: id}})
+}
+
+func (m *Mongo) GetByUUID(ctx context.Context, uuid string) (models.User, error) {
+	return m.findOne(ctx, bson.D{{Key: "uuid", Value: uuid}})
+}
+
+func (m *Mongo) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	return m.findOne(ctx, bson.D{{Key: "email", Value: email}})
+}
+
+func (m *Mongo) List(ctx context.Context, opts ListOptions) ([]models.User, error) {
+	ctx, cancel := m.opCtx(ctx)
+	defer cancel()
+	filter := bson.D{}
+	if !opts.IncludeDeleted {
+		filter = append(filter, notDeleted...)
+	}
+	if opts.NameContains != "" {
+		filter = append(filter, bson.E{Key: "name", Value: bson.D{
+			{Key: "$regex", Value: regexp.QuoteMeta(opts.NameContains)}, {Key: "$options", Value: "i"},
+		}})
+	}
+	if opts.EmailContains != "" {
+		filter = append(filter, bson.E{Key: "email", Value: bson.D{
+			{Key: "$regex", Value: regexp.QuoteMeta(opts.EmailContains)}, {Key: "$options", Value: "i"},
+		}})
+	}
+	if opts.AgeMin != nil || opts.AgeMax != nil {
+		bounds := bson.D{}
+		if opts.AgeMin != nil {
+			bounds = append(bounds, bson.E{Key: "$gte", Value: *opts.AgeMin})
+		}
+		if opts.AgeMax != nil {
+			bounds = append(bounds, bson.E{Key: "$lte", Value: *opts.AgeMax})
+		}
+		filter = append(filter, bson.E{Key: "age", Value: bounds})
+	}
+	sortDoc := bson.D{}
+	for _, k := range opts.Sort {
+		field := k.Field
+		if field == "id" {
+			field = "_id"
+		}
+		dir := 1
+		if k.Desc {
+			dir = -1
+		}
+		sortDoc = append(sortDoc, bson.E{Key: field, Value: dir})
+	}
+	sortDoc = append(sortDoc, bson.E{Key: "_id", Value: 1})
+	cur, err := m.users.Find(ctx, filter, options.Find().SetSort(sortDoc))
+	if err != nil {
+		return nil, mongoErr("list", err)
+	}
+	var all []models.User
+	if err := cur.All(ctx, &all); err != nil {
+		return nil, mongoErr("list", err)
+	}
+	return all, nil
+}
+
+func (m *Mongo) Create(ctx context.Context, u models.User) (models.User, error) {
+	ctx, cancel := m.opCtx(ctx)
+	defer cancel()
+	id, err := m.nextID(ctx)
+	if err != nil {
+		return models.User{}, mongoErr("create", err)
+	}
+	u.ID = id
+	u.UUID = NewUUID()
+	u.DeletedAt = nil
+	if _, err := m.users.InsertOne(ctx, u); err != nil {
+		return models.User{}, mongoErr("create", err)
+	}
+	return u, nil
+}
+
+func (m *Mongo) Update(ctx context.Context, u models.User) error {
+	ctx, cancel := m.opCtx(ctx)
+	defer cancel()
+	res, err := m.users.UpdateOne(ctx,
+		append(bson.D{{Key: "_id", Value: u.ID}}, notDeleted...),
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "name", Value: u.Name},
+			{Key: "email", Value: u.Email},
+			{Key: "age", Value: u.Age},
+		}}})
+	if err != nil {
+		return mongoErr("update", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (m *Mongo) Delete(ctx context.Context, id int) error {
+	ctx, cancel := m.opCtx(ctx)
+	defer cancel()
+	res, err := m.users.UpdateOne(ctx,
+		append(bson.D{{Key: "_id", Value: id}}, notDeleted...),
+		bson.D{{Key: "$set", Value: bson.D{{Key: "deleted_at", Value: time.Now().UTC()}}}})
+	if err != nil {
+		return mongoErr("delete", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (m *Mongo) Restore(ctx context.Context, id int) error {
+	ctx, cancel := m.opCtx(ctx)
+	defer cancel()
+	res, err := m.users.UpdateOne(ctx,
+		bson.D{
+			{Key: "_id", Value: id},
+			{Key: "deleted_at", Value: bson.D{{Key: "$exists", Value: true}}},
+		},
+		bson.D{{Key: "$unset", Value: bson.D{{Key: "deleted_at", Value: ""}}}})
+	if err != nil {
+		return mongoErr("restore", err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Purge permanently removes documents soft-deleted before cutoff.
+func (m *Mongo) Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	ctx, cancel := m.opCtx(ctx)
+	defer cancel()
+	filter := bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$lt", Value: cutoff}}}}
+	if dryRun {
+		n, err := m.users.CountDocuments(ctx, filter)
+		if err != nil {
+			return 0, mongoErr("purge count", err)
+		}
+		return int(n), nil
+	}
+	res, err := m.users.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, mongoErr("purge", err)
+	}
+	return int(res.DeletedCount), nil
+}
+
+// WithTx runs fn inside a MongoDB session transaction. This requires
+// the deployment to be a replica set or sharded cluster; standalone
+// servers reject transactions.
+func (m *Mongo) WithTx(ctx context.Context, fn func(Store) error) error {
+	if m.sc != nil {
+		return fn(m)
+	}
+	session, err := m.client.StartSession()
+	if err != nil {
+		return fmt.Errorf("store: mongo session: %w", err)
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		inTx := *m
+		inTx.sc = sc
+		return nil, fn(&inTx)
+	})
+	return err
+}
@@ -0,0 +1,223 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/divijg19/Iris/Web_Server/internal/migrations"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// MySQL is a Store backed by MySQL or MariaDB. It shares the
+// migration machinery with the other SQL backends through the "mysql"
+// dialect files.
+type MySQL struct {
+	db *sql.DB
+	q  sqlQueryer
+}
+
+// NewMySQL connects using a go-sql-driver DSN and ensures the schema
+// is migrated. The DSN should enable multiStatements and parseTime so
+// multi-statement migrations and TIMESTAMP scanning work.
+func NewMySQL(ctx context.Context, dsn string) (*MySQL, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("store: opening mysql: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: pinging mysql: %w", err)
+	}
+	if err := migrations.Migrate(ctx, db, "mysql"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &MySQL{db: db, q: db}, nil
+}
+
+// Close releases the underlying connection pool.
+func (m *MySQL) Close() error { return m.db.Close() }
+
+// myErr translates driver errors into the store's sentinel errors.
+func myErr(op string, err error) error {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == 1062 { // ER_DUP_ENTRY
+		return fmt.Errorf("store: mysql %s: %w", op, ErrConflict)
+	}
+	return fmt.Errorf("store: mysql %s: %w", op, err)
+}
+
+// getWhere runs the shared single-row lookup with one bound argument.
+func (m *MySQL) getWhere(ctx context.Context, op, where string, arg any) (models.User, error) {
+	var (
+		u    models.User
+		hash sql.NullString
+	)
+	err := m.q.QueryRowContext(ctx,
+		`SELECT id, uuid, name, email, age, password_hash FROM users WHERE `+where+` AND deleted_at IS NULL`, arg).
+		Scan(&u.ID, &u.UUID, &u.Name, &u.Email, &u.Age, &hash)
+	if err == sql.ErrNoRows {
+		return models.User{}, ErrNotFound
+	}
+	if err != nil {
+		return models.User{}, myErr(op, err)
+	}
+	u.PasswordHash = hash.String
+	return u, nil
+}
+
+func (m *MySQL) Get(ctx context.Context, id int) (models.User, error) {
+	return m.getWhere(ctx, "get", "id = ?", id)
+}
+
+func (m *MySQL) GetByUUID(ctx context.Context, uuid string) (models.User, error) {
+	return m.getWhere(ctx, "get by uuid", "uuid = ?", uuid)
+}
+
+func (m *MySQL) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	return m.getWhere(ctx, "get by email", "email = ?", email)
+}
+
+func (m *MySQL) List(ctx context.Context, opts ListOptions) ([]models.User, error) {
+	var all []models.User
+	err := m.Each(ctx, opts, func(u models.User) error {
+		all = append(all, u)
+		return nil
+	})
+	return all, err
+}
+
+// Each streams rows off the cursor without buffering the table.
+func (m *MySQL) Each(ctx context.Context, opts ListOptions, fn func(models.User) error) error {
+	where, args, order := sqlListClauses(opts)
+	rows, err := m.q.QueryContext(ctx,
+		`SELECT id, uuid, name, email, age, password_hash, deleted_at FROM users`+where+order, args...)
+	if err != nil {
+		return myErr("list", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			u       models.User
+			hash    sql.NullString
+			deleted sql.NullTime
+		)
+		if err := rows.Scan(&u.ID, &u.UUID, &u.Name, &u.Email, &u.Age, &hash, &deleted); err != nil {
+			return myErr("list scan", err)
+		}
+		u.PasswordHash = hash.String
+		if deleted.Valid {
+			t := deleted.Time
+			u.DeletedAt = &t
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (m *MySQL) Create(ctx context.Context, u models.User) (models.User, error) {
+	u.UUID = NewUUID()
+	res, err := m.q.ExecContext(ctx,
+		`INSERT INTO users (uuid, name, email, age, password_hash) VALUES (?, ?, ?, ?, ?)`,
+		u.UUID, u.Name, u.Email, u.Age, u.PasswordHash)
+	if err != nil {
+		return models.User{}, myErr("create", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.User{}, myErr("create", err)
+	}
+	u.ID = int(id)
+	return u, nil
+}
+
+func (m *MySQL) Update(ctx context.Context, u models.User) error {
+	res, err := m.q.ExecContext(ctx,
+		`UPDATE users SET name = ?, email = ?, age = ? WHERE id = ? AND deleted_at IS NULL`,
+		u.Name, u.Email, u.Age, u.ID)
+	if err != nil {
+		return myErr("update", err)
+	}
+	return rowsOrNotFound(res)
+}
+
+// Delete soft-deletes; Restore undoes it until the row is purged.
+func (m *MySQL) Delete(ctx context.Context, id int) error {
+	res, err := m.q.ExecContext(ctx,
+		`UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`, id)
+	if err != nil {
+		return myErr("delete", err)
+	}
+	return rowsOrNotFound(res)
+}
+
+func (m *MySQL) Restore(ctx context.Context, id int) error {
+	res, err := m.q.ExecContext(ctx,
+		`UPDATE users SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return myErr("restore", err)
+	}
+	return rowsOrNotFound(res)
+}
+
+// Purge hard-deletes rows soft-deleted before cutoff.
+func (m *MySQL) Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	if dryRun {
+		var n int
+		err := m.q.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff).Scan(&n)
+		if err != nil {
+			return 0, myErr("purge count", err)
+		}
+		return n, nil
+	}
+	res, err := m.q.ExecContext(ctx,
+		`DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff)
+	if err != nil {
+		return 0, myErr("purge", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// WithTx runs fn inside a database transaction, committing on success
+// and rolling back when fn returns an error.
+func (m *MySQL) WithTx(ctx context.Context, fn func(Store) error) error {
+	if _, nested := m.q.(*sql.Tx); nested {
+		return fn(m)
+	}
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("store: mysql begin: %w", err)
+	}
+	if err := fn(&MySQL{db: m.db, q: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// rowsOrNotFound maps a zero-row write result to ErrNotFound.
+func rowsOrNotFound(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Stats aggregates in the database via the shared SQL implementation.
+func (m *MySQL) Stats(ctx context.Context) (Stats, error) {
+	return sqlStats(ctx, m.q)
+}
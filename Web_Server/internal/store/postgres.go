@@ -0,0 +1,330 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+
+	"github.com/divijg19/Iris/Web_Server/internal/migrations"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// pgQueryer is the intersection of *pgxpool.Pool and pgx.Tx the store
+// uses, so the same query methods serve plain and transactional use.
+type pgQueryer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Postgres is a Store backed by PostgreSQL through a pgx connection
+// pool, intended for production deployments.
+type Postgres struct {
+	pool *pgxpool.Pool
+	q    pgQueryer
+}
+
+// NewPostgres connects to the database at dsn and ensures the schema
+// exists. maxConns bounds the pool size; zero keeps the pgx default.
+func NewPostgres(ctx context.Context, dsn string, maxConns int32) (*Postgres, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("store: parsing postgres dsn: %w", err)
+	}
+	if maxConns > 0 {
+		cfg.MaxConns = maxConns
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("store: connecting to postgres: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("store: pinging postgres: %w", err)
+	}
+	// Migrations run through the database/sql adapter over the same
+	// pool so both SQL backends share the migration machinery.
+	if err := migrations.Migrate(ctx, stdlib.OpenDBFromPool(pool), "postgres"); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return &Postgres{pool: pool, q: pool}, nil
+}
+
+// Close releases all pooled connections.
+func (p *Postgres) Close() error {
+	p.pool.Close()
+	return nil
+}
+
+// pgErr translates driver errors into the store's sentinel errors so
+// handlers can map them to 404/409 without importing pgx.
+func pgErr(op string, err error) error {
+	var pge *pgconn.PgError
+	if errors.As(err, &pge) && pge.Code == "23505" { // unique_violation
+		return fmt.Errorf("store: postgres %s: %w", op, ErrConflict)
+	}
+	return fmt.Errorf("store: postgres %s: %w", op, err)
+}
+
+// getWhere runs the shared single-row lookup with one bound argument.
+func (p *Postgres) getWhere(ctx context.Context, op, where string, arg any) (models.User, error) {
+	var (
+		u    models.User
+		hash *string
+	)
+	err := p.q.QueryRow(ctx,
+		`SELECT id, uuid, name, email, age, password_hash FROM users WHERE `+where+` AND deleted_at IS NULL`, arg).
+		Scan(&u.ID, &u.UUID, &u.Name, &u.Email, &u.Age, &hash)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.User{}, ErrNotFound
+	}
+	if err != nil {
+		return models.User{}, pgErr(op, err)
+	}
+	if hash != nil {
+		u.PasswordHash = *hash
+	}
+	return u, nil
+}
+
+func (p *Postgres) Get(ctx context.Context, id int) (models.User, error) {
+	return p.getWhere(ctx, "get", "id = $1", id)
+}
+
+func (p *Postgres) GetByUUID(ctx context.Context, uuid string) (models.User, error) {
+	return p.getWhere(ctx, "get by uuid", "uuid = $1", uuid)
+}
+
+func (p *Postgres) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	return p.getWhere(ctx, "get by email", "email = $1", email)
+}
+
+func (p *Postgres) List(ctx context.Context, opts ListOptions) ([]models.User, error) {
+	var all []models.User
+	err := p.Each(ctx, opts, func(u models.User) error {
+		all = append(all, u)
+		return nil
+	})
+	return all, err
+}
+
+// pgListClauses is the $n-placeholder, ILIKE-flavored counterpart of
+// sqlListClauses.
+func pgListClauses(opts ListOptions) (where string, args []any, order string) {
+	var conds []string
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if !opts.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if opts.NameContains != "" {
+		conds = append(conds, "name ILIKE "+arg("%"+opts.NameContains+"%"))
+	}
+	if opts.EmailContains != "" {
+		conds = append(conds, "email ILIKE "+arg("%"+opts.EmailContains+"%"))
+	}
+	if opts.AgeMin != nil {
+		conds = append(conds, "age >= "+arg(*opts.AgeMin))
+	}
+	if opts.AgeMax != nil {
+		conds = append(conds, "age <= "+arg(*opts.AgeMax))
+	}
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+	return where, args, " ORDER BY " + orderClause(opts.Sort)
+}
+
+// Each streams rows off the pgx cursor without buffering the table.
+func (p *Postgres) Each(ctx context.Context, opts ListOptions, fn func(models.User) error) error {
+	where, args, order := pgListClauses(opts)
+	rows, err := p.q.Query(ctx,
+		`SELECT id, uuid, name, email, age, password_hash, deleted_at FROM users`+where+order, args...)
+	if err != nil {
+		return pgErr("list", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			u    models.User
+			hash *string
+		)
+		if err := rows.Scan(&u.ID, &u.UUID, &u.Name, &u.Email, &u.Age, &hash, &u.DeletedAt); err != nil {
+			return pgErr("list scan", err)
+		}
+		if hash != nil {
+			u.PasswordHash = *hash
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Search implements Searcher with ILIKE plus pg_trgm similarity,
+// served by the GIN trigram indexes from migration 0005.
+func (p *Postgres) Search(ctx context.Context, query string, limit int) ([]models.User, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := p.q.Query(ctx, `
+		SELECT id, uuid, name, email, age FROM users
+		WHERE deleted_at IS NULL
+		  AND (name ILIKE '%' || $1 || '%' OR email ILIKE '%' || $1 || '%'
+		       OR similarity(name, $1) >= 0.3 OR similarity(email, $1) >= 0.3)
+		ORDER BY GREATEST(similarity(name, $1), similarity(email, $1)) DESC, id
+		LIMIT $2`, query, limit)
+	if err != nil {
+		return nil, pgErr("search", err)
+	}
+	defer rows.Close()
+	var out []models.User
+	for rows.Next() {
+		var u models.User
+		if err := rows.Scan(&u.ID, &u.UUID, &u.Name, &u.Email, &u.Age); err != nil {
+			return nil, pgErr("search scan", err)
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (p *Postgres) Create(ctx context.Context, u models.User) (models.User, error) {
+	u.UUID = NewUUID()
+	err := p.q.QueryRow(ctx,
+		`INSERT INTO users (uuid, name, email, age, password_hash) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+		u.UUID, u.Name, u.Email, u.Age, u.PasswordHash).Scan(&u.ID)
+	if err != nil {
+		return models.User{}, pgErr("create", err)
+	}
+	return u, nil
+}
+
+func (p *Postgres) Update(ctx context.Context, u models.User) error {
+	tag, err := p.q.Exec(ctx,
+		`UPDATE users SET name = $1, email = $2, age = $3 WHERE id = $4 AND deleted_at IS NULL`,
+		u.Name, u.Email, u.Age, u.ID)
+	if err != nil {
+		return pgErr("update", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *Postgres) Delete(ctx context.Context, id int) error {
+	tag, err := p.q.Exec(ctx,
+		`UPDATE users SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`, id)
+	if err != nil {
+		return pgErr("delete", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (p *Postgres) Restore(ctx context.Context, id int) error {
+	tag, err := p.q.Exec(ctx,
+		`UPDATE users SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return pgErr("restore", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Purge hard-deletes rows soft-deleted before cutoff.
+func (p *Postgres) Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	if dryRun {
+		var n int
+		err := p.q.QueryRow(ctx,
+			`SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL AND deleted_at < $1`, cutoff).Scan(&n)
+		if err != nil {
+			return 0, pgErr("purge count", err)
+		}
+		return n, nil
+	}
+	tag, err := p.q.Exec(ctx,
+		`DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < $1`, cutoff)
+	if err != nil {
+		return 0, pgErr("purge", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// WithTx runs fn inside a database transaction, committing on success
+// and rolling back when fn returns an error.
+func (p *Postgres) WithTx(ctx context.Context, fn func(Store) error) error {
+	if _, nested := p.q.(pgx.Tx); nested {
+		return fn(p)
+	}
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("store: postgres begin: %w", err)
+	}
+	if err := fn(&Postgres{pool: p.pool, q: tx}); err != nil {
+		tx.Rollback(ctx)
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Stats aggregates in the database rather than scanning rows in Go;
+// signup days still need the uuid column since the v7 timestamp is
+// opaque to SQL.
+func (p *Postgres) Stats(ctx context.Context) (Stats, error) {
+	st := newStats()
+	var deleted int
+	buckets := make([]*int64, 6)
+	err := p.q.QueryRow(ctx, `SELECT COUNT(*), COUNT(deleted_at),
+		SUM(CASE WHEN age <= 0 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age > 0 AND age < 18 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age BETWEEN 18 AND 29 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age BETWEEN 30 AND 44 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age BETWEEN 45 AND 64 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age >= 65 THEN 1 ELSE 0 END)
+		FROM users`).Scan(&st.Total, &deleted,
+		&buckets[0], &buckets[1], &buckets[2], &buckets[3], &buckets[4], &buckets[5])
+	if err != nil {
+		return Stats{}, fmt.Errorf("store: postgres stats: %w", err)
+	}
+	st.Deleted = deleted
+	st.Active = st.Total - deleted
+	for i, name := range statBucketNames {
+		if buckets[i] != nil && *buckets[i] > 0 {
+			st.AgeBuckets[name] = int(*buckets[i])
+		}
+	}
+
+	rows, err := p.q.Query(ctx, `SELECT uuid FROM users`)
+	if err != nil {
+		return Stats{}, fmt.Errorf("store: postgres stats: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return Stats{}, fmt.Errorf("store: postgres stats: %w", err)
+		}
+		if day := uuidDay(id); day != "" {
+			st.SignupsPerDay[day]++
+		}
+	}
+	return st, rows.Err()
+}
@@ -0,0 +1,169 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// replicaCooldown is how long a replica sits out after a failed read
+// before it is tried again.
+const replicaCooldown = 30 * time.Second
+
+// Replicated splits traffic between a primary and read replicas:
+// writes (and transactions) always hit the primary, reads rotate over
+// healthy replicas and fall back to the primary when none respond.
+// It implements Store, so handlers are unaware of the topology.
+type Replicated struct {
+	primary  Store
+	replicas []*replica
+	next     atomic.Uint64
+}
+
+// replica wraps a replica store with its health state: downUntil is
+// the unix-nano time before which the replica is skipped.
+type replica struct {
+	store     Store
+	downUntil atomic.Int64
+}
+
+func (r *replica) healthy() bool {
+	return time.Now().UnixNano() >= r.downUntil.Load()
+}
+
+func (r *replica) markDown() {
+	r.downUntil.Store(time.Now().Add(replicaCooldown).UnixNano())
+}
+
+// NewReplicated wraps a primary store and its replicas. With no
+// replicas it degrades to serving everything from the primary.
+func NewReplicated(primary Store, replicas ...Store) *Replicated {
+	rw := &Replicated{primary: primary}
+	for _, s := range replicas {
+		rw.replicas = append(rw.replicas, &replica{store: s})
+	}
+	return rw
+}
+
+// read runs fn against one healthy replica, falling back to the
+// primary when the replica fails (marking it down) or none are up.
+// ErrNotFound is a valid answer, not a health problem.
+func (rw *Replicated) read(fn func(Store) error) error {
+	n := len(rw.replicas)
+	if n > 0 {
+		start := int(rw.next.Add(1))
+		for i := 0; i < n; i++ {
+			rep := rw.replicas[(start+i)%n]
+			if !rep.healthy() {
+				continue
+			}
+			err := fn(rep.store)
+			if err == nil || errors.Is(err, ErrNotFound) {
+				return err
+			}
+			rep.markDown()
+		}
+	}
+	return fn(rw.primary)
+}
+
+func (rw *Replicated) Get(ctx context.Context, id int) (models.User, error) {
+	var u models.User
+	err := rw.read(func(s Store) error {
+		var err error
+		u, err = s.Get(ctx, id)
+		return err
+	})
+	return u, err
+}
+
+func (rw *Replicated) GetByUUID(ctx context.Context, uuid string) (models.User, error) {
+	var u models.User
+	err := rw.read(func(s Store) error {
+		var err error
+		u, err = s.GetByUUID(ctx, uuid)
+		return err
+	})
+	return u, err
+}
+
+func (rw *Replicated) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	var u models.User
+	err := rw.read(func(s Store) error {
+		var err error
+		u, err = s.GetByEmail(ctx, email)
+		return err
+	})
+	return u, err
+}
+
+func (rw *Replicated) List(ctx context.Context, opts ListOptions) ([]models.User, error) {
+	var all []models.User
+	err := rw.read(func(s Store) error {
+		var err error
+		all, err = s.List(ctx, opts)
+		return err
+	})
+	return all, err
+}
+
+// Each streams from a replica when possible. Once rows have started
+// flowing there is no clean mid-stream fallback, so only the initial
+// failure reroutes.
+func (rw *Replicated) Each(ctx context.Context, opts ListOptions, fn func(models.User) error) error {
+	return rw.read(func(s Store) error {
+		return Each(ctx, s, opts, fn)
+	})
+}
+
+func (rw *Replicated) Create(ctx context.Context, u models.User) (models.User, error) {
+	return rw.primary.Create(ctx, u)
+}
+
+func (rw *Replicated) Update(ctx context.Context, u models.User) error {
+	return rw.primary.Update(ctx, u)
+}
+
+func (rw *Replicated) Delete(ctx context.Context, id int) error {
+	return rw.primary.Delete(ctx, id)
+}
+
+func (rw *Replicated) Restore(ctx context.Context, id int) error {
+	return rw.primary.Restore(ctx, id)
+}
+
+// WithTx always runs on the primary: transactions read their own
+// writes, which replicas cannot guarantee under replication lag.
+func (rw *Replicated) WithTx(ctx context.Context, fn func(Store) error) error {
+	return rw.primary.WithTx(ctx, fn)
+}
+
+// Purge runs on the primary; replication carries the deletions over.
+func (rw *Replicated) Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	p, ok := rw.primary.(Purger)
+	if !ok {
+		return 0, errors.New("store: primary does not support purge")
+	}
+	return p.Purge(ctx, cutoff, dryRun)
+}
+
+// Close closes the primary and every replica, keeping the first error.
+func (rw *Replicated) Close() error {
+	var first error
+	closeOne := func(s Store) {
+		if c, ok := s.(io.Closer); ok {
+			if err := c.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	closeOne(rw.primary)
+	for _, rep := range rw.replicas {
+		closeOne(rep.store)
+	}
+	return first
+}
@@ -0,0 +1,106 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// Searcher is an optional Store capability: backends with a native
+// fuzzy-match path (trigram indexes and the like) implement it.
+type Searcher interface {
+	// Search returns up to limit live users whose name or email
+	// matches query, best matches first.
+	Search(ctx context.Context, query string, limit int) ([]models.User, error)
+}
+
+// searchThreshold is the minimum trigram similarity that still counts
+// as a match, mirroring pg_trgm's default.
+const searchThreshold = 0.3
+
+// SearchUsers finds users matching query, using the backend's
+// Searcher implementation when available and an in-process scan over
+// Each otherwise.
+func SearchUsers(ctx context.Context, s Store, query string, limit int) ([]models.User, error) {
+	if sr, ok := s.(Searcher); ok {
+		return sr.Search(ctx, query, limit)
+	}
+	type scored struct {
+		u     models.User
+		score float64
+	}
+	var matches []scored
+	err := Each(ctx, s, ListOptions{}, func(u models.User) error {
+		if score := matchScore(u, query); score >= searchThreshold {
+			matches = append(matches, scored{u: u, score: score})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+		return matches[i].u.ID < matches[j].u.ID
+	})
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	out := make([]models.User, len(matches))
+	for i, m := range matches {
+		out[i] = m.u
+	}
+	return out, nil
+}
+
+// matchScore rates how well a user matches the query: 1 for a
+// case-insensitive prefix of name or email, 0.8 for a substring, and
+// the trigram similarity otherwise.
+func matchScore(u models.User, query string) float64 {
+	q := strings.ToLower(query)
+	best := 0.0
+	for _, field := range []string{strings.ToLower(u.Name), strings.ToLower(u.Email)} {
+		switch {
+		case strings.HasPrefix(field, q):
+			return 1
+		case strings.Contains(field, q):
+			best = max(best, 0.8)
+		default:
+			best = max(best, similarity(field, q))
+		}
+	}
+	return best
+}
+
+// trigrams returns the padded three-character shingles of s, the same
+// construction pg_trgm uses ("  ab", " abc", ..., "bc ").
+func trigrams(s string) map[string]struct{} {
+	set := map[string]struct{}{}
+	for _, word := range strings.Fields(strings.ToLower(s)) {
+		padded := "  " + word + " "
+		for i := 0; i+3 <= len(padded); i++ {
+			set[padded[i:i+3]] = struct{}{}
+		}
+	}
+	return set
+}
+
+// similarity is the Jaccard similarity of the two strings' trigram
+// sets.
+func similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
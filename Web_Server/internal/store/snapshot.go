@@ -0,0 +1,224 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// PersistConfig controls how the in-memory store persists itself.
+type PersistConfig struct {
+	// SnapshotPath is the JSON snapshot file; empty disables
+	// persistence entirely.
+	SnapshotPath string
+
+	// WALPath, when set, adds an operation log replayed over the
+	// snapshot at startup and compacted on each snapshot.
+	WALPath string
+
+	// Interval is the fixed snapshot timer; it defaults to a minute
+	// and is ignored in OnChange mode.
+	Interval time.Duration
+
+	// OnChange snapshots shortly after mutations instead of on a
+	// fixed timer, so an idle store never rewrites its file.
+	OnChange bool
+
+	// Debounce is how long after the first pending mutation an
+	// OnChange snapshot is written; it defaults to two seconds.
+	Debounce time.Duration
+
+	// Fsync forces snapshot writes and WAL appends to stable storage.
+	Fsync bool
+}
+
+// NewMemoryWithPersistence returns a Memory store persisted per cfg:
+// its state is restored at startup and written back on the configured
+// schedule and on Close.
+func NewMemoryWithPersistence(cfg PersistConfig) (*Memory, error) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Debounce <= 0 {
+		cfg.Debounce = 2 * time.Second
+	}
+	m := NewMemory()
+	m.snapshotPath = cfg.SnapshotPath
+	m.fsync = cfg.Fsync
+	if err := m.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if cfg.WALPath != "" {
+		if err := m.replayWAL(cfg.WALPath); err != nil {
+			return nil, err
+		}
+		w, err := openWAL(cfg.WALPath, cfg.Fsync)
+		if err != nil {
+			return nil, err
+		}
+		m.wal = w
+	}
+	if cfg.OnChange {
+		m.changed = make(chan struct{}, 1)
+	}
+	m.stopSnapshot = make(chan struct{})
+	m.snapshotDone = make(chan struct{})
+	go m.persistLoop(cfg)
+	return m, nil
+}
+
+// NewMemoryWithSnapshot returns a Memory store that restores its
+// contents from the JSON file at path on startup and rewrites it every
+// interval and on Close, so the default in-memory mode survives
+// restarts.
+func NewMemoryWithSnapshot(path string, interval time.Duration) (*Memory, error) {
+	return NewMemoryWithPersistence(PersistConfig{SnapshotPath: path, Interval: interval})
+}
+
+// loadSnapshot restores the map from the snapshot file; a missing
+// file simply means a fresh store.
+func (m *Memory) loadSnapshot() error {
+	raw, err := os.ReadFile(m.snapshotPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("store: reading snapshot %q: %w", m.snapshotPath, err)
+	}
+	var all []models.User
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return fmt.Errorf("store: decoding snapshot %q: %w", m.snapshotPath, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range all {
+		m.users[u.ID] = u
+		if u.UUID != "" {
+			m.byUUID[u.UUID] = u.ID
+		}
+		if u.DeletedAt == nil {
+			m.byEmail[u.Email] = u.ID
+		}
+		m.bumpNextID(u.ID)
+	}
+	m.publishLocked()
+	return nil
+}
+
+// SaveSnapshot writes the current contents to the snapshot file,
+// atomically via a temp file and rename. When an operation log is in
+// use, the write lock is held across the snapshot and the log is
+// compacted, so no logged operation is ever lost or replayed twice.
+func (m *Memory) SaveSnapshot() error {
+	if m.snapshotPath == "" {
+		return nil
+	}
+	if m.wal != nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+	} else {
+		m.mu.RLock()
+	}
+	all := make([]models.User, 0, len(m.users))
+	for _, u := range m.users {
+		all = append(all, u)
+	}
+	if m.wal == nil {
+		m.mu.RUnlock()
+	}
+
+	raw, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("store: encoding snapshot: %w", err)
+	}
+	dir := filepath.Dir(m.snapshotPath)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if m.fsync {
+		if err := tmp.Sync(); err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+			return err
+		}
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), m.snapshotPath); err != nil {
+		return err
+	}
+	if m.wal != nil {
+		return m.wal.truncate()
+	}
+	return nil
+}
+
+func (m *Memory) persistLoop(cfg PersistConfig) {
+	defer close(m.snapshotDone)
+	if m.changed == nil {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.SaveSnapshot(); err != nil {
+					// Keep serving; the next tick retries.
+					fmt.Fprintf(os.Stderr, "iris: %v\n", err)
+				}
+			case <-m.stopSnapshot:
+				return
+			}
+		}
+	}
+
+	// OnChange mode: the first mutation after a snapshot arms a
+	// debounce timer. Later mutations do not push it out, so sustained
+	// write load still snapshots every Debounce rather than never.
+	var fire <-chan time.Time
+	for {
+		select {
+		case <-m.changed:
+			if fire == nil {
+				fire = time.After(cfg.Debounce)
+			}
+		case <-fire:
+			fire = nil
+			if err := m.SaveSnapshot(); err != nil {
+				fmt.Fprintf(os.Stderr, "iris: %v\n", err)
+			}
+		case <-m.stopSnapshot:
+			return
+		}
+	}
+}
+
+// Close stops the snapshot loop (if any) and writes a final snapshot.
+func (m *Memory) Close() error {
+	if m.snapshotPath == "" {
+		return nil
+	}
+	close(m.stopSnapshot)
+	<-m.snapshotDone
+	err := m.SaveSnapshot()
+	if m.wal != nil {
+		if cerr := m.wal.close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
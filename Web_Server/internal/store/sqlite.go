@@ -0,0 +1,333 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite" // pure-Go sqlite driver
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"github.com/divijg19/Iris/Web_Server/internal/migrations"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// sqlQueryer is the intersection of *sql.DB and *sql.Tx the store
+// uses, so the same query methods serve plain and transactional use.
+type sqlQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// SQLite is a Store backed by a single-file SQLite database, for
+// deployments that need durability without an external database.
+type SQLite struct {
+	db *sql.DB
+	q  sqlQueryer
+}
+
+// NewSQLite opens (creating if necessary) the database at path and
+// ensures the schema exists.
+func NewSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("store: opening sqlite %q: %w", path, err)
+	}
+	// The driver is file-based; a single writer avoids SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
+	if err := migrations.Migrate(context.Background(), db, "sqlite"); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLite{db: db, q: db}, nil
+}
+
+// Close releases the underlying database handle.
+func (s *SQLite) Close() error { return s.db.Close() }
+
+// liteErr translates driver errors into the store's sentinel errors so
+// handlers can map them to 404/409 without importing the driver.
+func liteErr(op string, err error) error {
+	var se *sqlite.Error
+	if errors.As(err, &se) && se.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE {
+		return fmt.Errorf("store: sqlite %s: %w", op, ErrConflict)
+	}
+	return fmt.Errorf("store: sqlite %s: %w", op, err)
+}
+
+// getWhere runs the shared single-row lookup with one bound argument.
+func (s *SQLite) getWhere(ctx context.Context, op, where string, arg any) (models.User, error) {
+	var (
+		u    models.User
+		hash sql.NullString
+	)
+	err := s.q.QueryRowContext(ctx,
+		`SELECT id, uuid, name, email, age, password_hash FROM users WHERE `+where+` AND deleted_at IS NULL`, arg).
+		Scan(&u.ID, &u.UUID, &u.Name, &u.Email, &u.Age, &hash)
+	if err == sql.ErrNoRows {
+		return models.User{}, ErrNotFound
+	}
+	if err != nil {
+		return models.User{}, liteErr(op, err)
+	}
+	u.PasswordHash = hash.String
+	return u, nil
+}
+
+func (s *SQLite) Get(ctx context.Context, id int) (models.User, error) {
+	return s.getWhere(ctx, "get", "id = ?", id)
+}
+
+func (s *SQLite) GetByUUID(ctx context.Context, uuid string) (models.User, error) {
+	return s.getWhere(ctx, "get by uuid", "uuid = ?", uuid)
+}
+
+func (s *SQLite) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	return s.getWhere(ctx, "get by email", "email = ?", email)
+}
+
+// sqlListClauses renders the list filters and sort into WHERE and
+// ORDER BY fragments for the ?-placeholder backends (SQLite, MySQL),
+// so filtering happens in the database rather than in process.
+func sqlListClauses(opts ListOptions) (where string, args []any, order string) {
+	var conds []string
+	if !opts.IncludeDeleted {
+		conds = append(conds, "deleted_at IS NULL")
+	}
+	if opts.NameContains != "" {
+		conds = append(conds, "LOWER(name) LIKE ?")
+		args = append(args, "%"+strings.ToLower(opts.NameContains)+"%")
+	}
+	if opts.EmailContains != "" {
+		conds = append(conds, "LOWER(email) LIKE ?")
+		args = append(args, "%"+strings.ToLower(opts.EmailContains)+"%")
+	}
+	if opts.AgeMin != nil {
+		conds = append(conds, "age >= ?")
+		args = append(args, *opts.AgeMin)
+	}
+	if opts.AgeMax != nil {
+		conds = append(conds, "age <= ?")
+		args = append(args, *opts.AgeMax)
+	}
+	if len(conds) > 0 {
+		where = " WHERE " + strings.Join(conds, " AND ")
+	}
+	return where, args, " ORDER BY " + orderClause(opts.Sort)
+}
+
+// orderClause renders sort keys as an ORDER BY body. Fields are
+// checked against the sort whitelist again here so a hand-built
+// SortKey can never smuggle SQL into the clause.
+func orderClause(keys []SortKey) string {
+	var parts []string
+	for _, k := range keys {
+		if !sortFields[k.Field] {
+			continue
+		}
+		if k.Desc {
+			parts = append(parts, k.Field+" DESC")
+			continue
+		}
+		parts = append(parts, k.Field)
+	}
+	return strings.Join(append(parts, "id"), ", ")
+}
+
+func (s *SQLite) List(ctx context.Context, opts ListOptions) ([]models.User, error) {
+	var all []models.User
+	err := s.Each(ctx, opts, func(u models.User) error {
+		all = append(all, u)
+		return nil
+	})
+	return all, err
+}
+
+// Each streams rows straight off the result cursor, one scan per
+// callback, so exports never hold the full table in memory.
+func (s *SQLite) Each(ctx context.Context, opts ListOptions, fn func(models.User) error) error {
+	where, args, order := sqlListClauses(opts)
+	rows, err := s.q.QueryContext(ctx,
+		`SELECT id, uuid, name, email, age, password_hash, deleted_at FROM users`+where+order, args...)
+	if err != nil {
+		return liteErr("list", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			u       models.User
+			hash    sql.NullString
+			deleted sql.NullTime
+		)
+		if err := rows.Scan(&u.ID, &u.UUID, &u.Name, &u.Email, &u.Age, &hash, &deleted); err != nil {
+			return liteErr("list scan", err)
+		}
+		u.PasswordHash = hash.String
+		if deleted.Valid {
+			t := deleted.Time
+			u.DeletedAt = &t
+		}
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (s *SQLite) Create(ctx context.Context, u models.User) (models.User, error) {
+	u.UUID = NewUUID()
+	res, err := s.q.ExecContext(ctx,
+		`INSERT INTO users (uuid, name, email, age, password_hash) VALUES (?, ?, ?, ?, ?)`,
+		u.UUID, u.Name, u.Email, u.Age, u.PasswordHash)
+	if err != nil {
+		return models.User{}, liteErr("create", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return models.User{}, liteErr("create", err)
+	}
+	u.ID = int(id)
+	return u, nil
+}
+
+func (s *SQLite) Update(ctx context.Context, u models.User) error {
+	res, err := s.q.ExecContext(ctx,
+		`UPDATE users SET name = ?, email = ?, age = ? WHERE id = ? AND deleted_at IS NULL`,
+		u.Name, u.Email, u.Age, u.ID)
+	if err != nil {
+		return liteErr("update", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return liteErr("update", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Delete soft-deletes; Restore undoes it until the row is purged.
+func (s *SQLite) Delete(ctx context.Context, id int) error {
+	res, err := s.q.ExecContext(ctx,
+		`UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE id = ? AND deleted_at IS NULL`, id)
+	if err != nil {
+		return liteErr("delete", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return liteErr("delete", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLite) Restore(ctx context.Context, id int) error {
+	res, err := s.q.ExecContext(ctx,
+		`UPDATE users SET deleted_at = NULL WHERE id = ? AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return liteErr("restore", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return liteErr("restore", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Purge hard-deletes rows soft-deleted before cutoff.
+func (s *SQLite) Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	if dryRun {
+		var n int
+		err := s.q.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff).Scan(&n)
+		if err != nil {
+			return 0, liteErr("purge count", err)
+		}
+		return n, nil
+	}
+	res, err := s.q.ExecContext(ctx,
+		`DELETE FROM users WHERE deleted_at IS NOT NULL AND deleted_at < ?`, cutoff)
+	if err != nil {
+		return 0, liteErr("purge", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// WithTx runs fn inside a database transaction, committing on success
+// and rolling back when fn returns an error.
+func (s *SQLite) WithTx(ctx context.Context, fn func(Store) error) error {
+	if _, nested := s.q.(*sql.Tx); nested {
+		return fn(s)
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return liteErr("begin", err)
+	}
+	if err := fn(&SQLite{db: s.db, q: tx}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// sqlStats aggregates the stats counters for the database/sql
+// backends: one aggregate query for counts and age buckets, then a
+// uuid-only scan for signup days (the v7 timestamp cannot be unpacked
+// portably in SQL).
+func sqlStats(ctx context.Context, q sqlQueryer) (Stats, error) {
+	st := newStats()
+	var deleted int
+	buckets := make([]sql.NullInt64, 6)
+	err := q.QueryRowContext(ctx, `SELECT COUNT(*), COUNT(deleted_at),
+		SUM(CASE WHEN age <= 0 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age > 0 AND age < 18 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age BETWEEN 18 AND 29 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age BETWEEN 30 AND 44 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age BETWEEN 45 AND 64 THEN 1 ELSE 0 END),
+		SUM(CASE WHEN age >= 65 THEN 1 ELSE 0 END)
+		FROM users`).Scan(&st.Total, &deleted,
+		&buckets[0], &buckets[1], &buckets[2], &buckets[3], &buckets[4], &buckets[5])
+	if err != nil {
+		return Stats{}, fmt.Errorf("store: stats: %w", err)
+	}
+	st.Deleted = deleted
+	st.Active = st.Total - deleted
+	for i, name := range statBucketNames {
+		if buckets[i].Int64 > 0 {
+			st.AgeBuckets[name] = int(buckets[i].Int64)
+		}
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT uuid FROM users`)
+	if err != nil {
+		return Stats{}, fmt.Errorf("store: stats: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return Stats{}, fmt.Errorf("store: stats: %w", err)
+		}
+		if day := uuidDay(id); day != "" {
+			st.SignupsPerDay[day]++
+		}
+	}
+	return st, rows.Err()
+}
+
+// Stats aggregates in the database rather than scanning rows in Go.
+func (s *SQLite) Stats(ctx context.Context) (Stats, error) {
+	return sqlStats(ctx, s.q)
+}
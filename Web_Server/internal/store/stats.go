@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// Stats is the aggregate view of the user table served by
+// /users/stats.
+type Stats struct {
+	Total         int            `json:"total"`
+	Active        int            `json:"active"`
+	Deleted       int            `json:"deleted"`
+	AgeBuckets    map[string]int `json:"age_buckets"`
+	SignupsPerDay map[string]int `json:"signups_per_day"`
+}
+
+// Aggregator is an optional capability: backends that can aggregate
+// natively implement it and spare the generic row scan.
+type Aggregator interface {
+	Stats(ctx context.Context) (Stats, error)
+}
+
+// UserStats computes aggregate stats through the backend's Aggregator
+// when it has one, falling back to a streaming Each scan — rows are
+// folded into counters one at a time, never collected into a slice.
+func UserStats(ctx context.Context, s Store) (Stats, error) {
+	if agg, ok := s.(Aggregator); ok {
+		return agg.Stats(ctx)
+	}
+	stats := newStats()
+	err := Each(ctx, s, ListOptions{IncludeDeleted: true}, func(u models.User) error {
+		stats.add(u)
+		return nil
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+	return stats, nil
+}
+
+func newStats() Stats {
+	return Stats{
+		AgeBuckets:    map[string]int{},
+		SignupsPerDay: map[string]int{},
+	}
+}
+
+// add folds one user into the counters.
+func (st *Stats) add(u models.User) {
+	st.Total++
+	if u.DeletedAt != nil {
+		st.Deleted++
+	} else {
+		st.Active++
+	}
+	st.AgeBuckets[ageBucket(u.Age)]++
+	if day := uuidDay(u.UUID); day != "" {
+		st.SignupsPerDay[day]++
+	}
+}
+
+// statBucketNames orders the age buckets the way the SQL aggregates
+// emit them.
+var statBucketNames = []string{"unknown", "under-18", "18-29", "30-44", "45-64", "65-plus"}
+
+// ageBucket assigns an age to its distribution bucket; zero means the
+// age was never provided.
+func ageBucket(age int) string {
+	switch {
+	case age <= 0:
+		return "unknown"
+	case age < 18:
+		return "under-18"
+	case age < 30:
+		return "18-29"
+	case age < 45:
+		return "30-44"
+	case age < 65:
+		return "45-64"
+	default:
+		return "65-plus"
+	}
+}
+
+// uuidDay recovers the creation day from a user's UUID. NewUUID has
+// always issued time-ordered v7 values, whose first 48 bits are the
+// creation time in milliseconds — so the UUID doubles as a created-at
+// column the schema never had.
+func uuidDay(id string) string {
+	hex := strings.ReplaceAll(id, "-", "")
+	if len(hex) < 12 {
+		return ""
+	}
+	ms, err := strconv.ParseInt(hex[:12], 16, 64)
+	if err != nil {
+		return ""
+	}
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
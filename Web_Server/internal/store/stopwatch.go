@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/timing"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// Stopwatch decorates a Store so every repository call adds to the
+// request's "store" Server-Timing stage. Like Traced, the optional
+// capabilities go to the inner store's native implementation when it
+// has one and through the package helpers otherwise.
+type Stopwatch struct {
+	inner Store
+}
+
+// NewStopwatch wraps inner. Wrap only when Server-Timing is enabled;
+// without a timing.Recorder in the context each call degrades to a
+// method-call's worth of overhead.
+func NewStopwatch(inner Store) *Stopwatch {
+	return &Stopwatch{inner: inner}
+}
+
+func (s *Stopwatch) Get(ctx context.Context, id int) (models.User, error) {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.Get(ctx, id)
+}
+
+func (s *Stopwatch) GetByUUID(ctx context.Context, uuid string) (models.User, error) {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.GetByUUID(ctx, uuid)
+}
+
+func (s *Stopwatch) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.GetByEmail(ctx, email)
+}
+
+func (s *Stopwatch) List(ctx context.Context, opts ListOptions) ([]models.User, error) {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.List(ctx, opts)
+}
+
+func (s *Stopwatch) Create(ctx context.Context, u models.User) (models.User, error) {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.Create(ctx, u)
+}
+
+func (s *Stopwatch) Update(ctx context.Context, u models.User) error {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.Update(ctx, u)
+}
+
+func (s *Stopwatch) Delete(ctx context.Context, id int) error {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.Delete(ctx, id)
+}
+
+func (s *Stopwatch) Restore(ctx context.Context, id int) error {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.Restore(ctx, id)
+}
+
+// WithTx times the transaction as a whole and hands fn the bare inner
+// view: the calls inside already run on the stopwatch's clock.
+func (s *Stopwatch) WithTx(ctx context.Context, fn func(Store) error) error {
+	defer timing.Stage(ctx, "store")()
+	return s.inner.WithTx(ctx, fn)
+}
+
+// Each streams through the inner store's Iterator when it has one.
+func (s *Stopwatch) Each(ctx context.Context, opts ListOptions, fn func(models.User) error) error {
+	defer timing.Stage(ctx, "store")()
+	return Each(ctx, s.inner, opts, fn)
+}
+
+// Search uses the inner store's native Searcher when available, the
+// in-process scan otherwise.
+func (s *Stopwatch) Search(ctx context.Context, query string, limit int) ([]models.User, error) {
+	defer timing.Stage(ctx, "store")()
+	return SearchUsers(ctx, s.inner, query, limit)
+}
+
+// Stats aggregates natively when the inner store can.
+func (s *Stopwatch) Stats(ctx context.Context) (Stats, error) {
+	defer timing.Stage(ctx, "store")()
+	return UserStats(ctx, s.inner)
+}
+
+// Purge forwards to the inner store's Purger; every shipped backend
+// has one, but a decorator cannot prove that statically.
+func (s *Stopwatch) Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	p, ok := s.inner.(Purger)
+	if !ok {
+		return 0, errors.New("store: inner store does not support purge")
+	}
+	defer timing.Stage(ctx, "store")()
+	return p.Purge(ctx, cutoff, dryRun)
+}
+
+// Close forwards to the inner store when it holds resources.
+func (s *Stopwatch) Close() error {
+	if c, ok := s.inner.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Package store defines the user repository interface and its
+// implementations. HTTP handlers depend only on the Store interface,
+// so backends (in-memory, SQL, embedded KV) can be swapped without
+// touching handler code.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// NewUUID returns a fresh UUIDv7 for a user record. v7 IDs are
+// time-ordered, which keeps index pages hot on the SQL backends.
+func NewUUID() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// ErrNotFound is returned when no user exists with the requested ID.
+var ErrNotFound = errors.New("store: user not found")
+
+// ErrConflict is returned when a write violates a uniqueness
+// constraint; handlers surface it as 409 Conflict.
+var ErrConflict = errors.New("store: conflict")
+
+// Store is the user repository. All methods are safe for concurrent
+// use and honor context cancellation where the backend supports it.
+type Store interface {
+	// Get returns the user with the given ID or ErrNotFound.
+	Get(ctx context.Context, id int) (models.User, error)
+
+	// GetByUUID returns the user with the given UUID or ErrNotFound.
+	GetByUUID(ctx context.Context, uuid string) (models.User, error)
+
+	// GetByEmail returns the live user with the given email or
+	// ErrNotFound; soft-deleted users are not matched.
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+
+	// List returns users ordered by ascending ID, excluding
+	// soft-deleted ones unless opts says otherwise.
+	List(ctx context.Context, opts ListOptions) ([]models.User, error)
+
+	// Create stores a new user and returns it with its assigned ID.
+	Create(ctx context.Context, u models.User) (models.User, error)
+
+	// Update replaces the user with u.ID or returns ErrNotFound.
+	Update(ctx context.Context, u models.User) error
+
+	// Delete soft-deletes the user with the given ID or returns
+	// ErrNotFound.
+	Delete(ctx context.Context, id int) error
+
+	// Restore clears the soft-delete marker on a deleted user, or
+	// returns ErrNotFound if no deleted user has that ID.
+	Restore(ctx context.Context, id int) error
+
+	// WithTx runs fn atomically where the backend supports it: the
+	// SQL backends use a database transaction (rolled back when fn
+	// errors), bbolt a single update transaction, and the in-memory
+	// store holds its write lock for the duration. The Store passed
+	// to fn is only valid inside fn and must not be retained.
+	WithTx(ctx context.Context, fn func(Store) error) error
+}
+
+// Purger is an optional Store capability: backends that can
+// permanently remove soft-deleted users implement it.
+type Purger interface {
+	// Purge hard-deletes users soft-deleted before cutoff, returning
+	// how many were removed. With dryRun it only counts them.
+	Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error)
+}
+
+// Iterator is an optional Store capability: backends that can stream
+// rows without materializing the whole result implement it.
+type Iterator interface {
+	// Each calls fn for every user List would return, in ID order,
+	// stopping early if fn returns an error.
+	Each(ctx context.Context, opts ListOptions, fn func(models.User) error) error
+}
+
+// Each streams users from s, using its Iterator implementation when
+// available and falling back to a plain List otherwise.
+func Each(ctx context.Context, s Store, opts ListOptions, fn func(models.User) error) error {
+	if it, ok := s.(Iterator); ok {
+		return it.Each(ctx, opts, fn)
+	}
+	all, err := s.List(ctx, opts)
+	if err != nil {
+		return err
+	}
+	for _, u := range all {
+		if err := fn(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListOptions controls List behavior.
+type ListOptions struct {
+	// IncludeDeleted also returns soft-deleted users.
+	IncludeDeleted bool
+
+	// NameContains and EmailContains are case-insensitive substring
+	// filters; empty means no filter.
+	NameContains  string
+	EmailContains string
+
+	// AgeMin and AgeMax bound the age inclusively when non-nil.
+	AgeMin *int
+	AgeMax *int
+
+	// Sort orders the result; empty means ascending ID. Backends that
+	// can (the SQL stores, Mongo) push these down to the database.
+	Sort []SortKey
+}
+
+// SortKey orders results by one field.
+type SortKey struct {
+	// Field is "id", "name", "email", or "age".
+	Field string
+	Desc  bool
+}
+
+// sortFields whitelists what ParseSort accepts; the SQL builders rely
+// on this to keep user input out of ORDER BY clauses.
+var sortFields = map[string]bool{"id": true, "name": true, "email": true, "age": true}
+
+// ParseSort parses a query-style sort spec like "name,-age" (a
+// leading minus meaning descending) into sort keys.
+func ParseSort(spec string) ([]SortKey, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	var keys []SortKey
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.TrimSpace(field)
+		key := SortKey{Field: field}
+		if rest, ok := strings.CutPrefix(field, "-"); ok {
+			key = SortKey{Field: rest, Desc: true}
+		}
+		if !sortFields[key.Field] {
+			return nil, fmt.Errorf("store: cannot sort by %q", key.Field)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// Matches applies the field filters (not IncludeDeleted, which every
+// backend handles natively) to one user, for backends that filter in
+// process.
+func (o ListOptions) Matches(u models.User) bool {
+	if o.NameContains != "" && !strings.Contains(strings.ToLower(u.Name), strings.ToLower(o.NameContains)) {
+		return false
+	}
+	if o.EmailContains != "" && !strings.Contains(strings.ToLower(u.Email), strings.ToLower(o.EmailContains)) {
+		return false
+	}
+	if o.AgeMin != nil && u.Age < *o.AgeMin {
+		return false
+	}
+	if o.AgeMax != nil && u.Age > *o.AgeMax {
+		return false
+	}
+	return true
+}
+
+// SortUsers orders users by the given keys in process, with ascending
+// ID as the final tiebreak. Backends that cannot push sorting down
+// call this after collecting.
+func SortUsers(all []models.User, keys []SortKey) {
+	sort.SliceStable(all, func(i, j int) bool {
+		for _, k := range keys {
+			var less, greater bool
+			switch k.Field {
+			case "name":
+				less, greater = all[i].Name < all[j].Name, all[i].Name > all[j].Name
+			case "email":
+				less, greater = all[i].Email < all[j].Email, all[i].Email > all[j].Email
+			case "age":
+				less, greater = all[i].Age < all[j].Age, all[i].Age > all[j].Age
+			default: // "id"
+				less, greater = all[i].ID < all[j].ID, all[i].ID > all[j].ID
+			}
+			if less || greater {
+				if k.Desc {
+					return greater
+				}
+				return less
+			}
+		}
+		return all[i].ID < all[j].ID
+	})
+}
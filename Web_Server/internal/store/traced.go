@@ -0,0 +1,159 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/divijg19/Iris/Web_Server/internal/telemetry"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// Traced decorates a Store with one OpenTelemetry span per repository
+// call. Like Replicated, it forwards the optional capabilities: when
+// the inner store has a native implementation it is used, otherwise
+// the call goes through the package helper and its fallback path.
+type Traced struct {
+	inner Store
+}
+
+// NewTraced wraps inner so every call is recorded as a "store.*"
+// span. Wrap only when tracing is enabled; the decorator does not
+// check again.
+func NewTraced(inner Store) *Traced {
+	return &Traced{inner: inner}
+}
+
+// start opens the span for one operation. Spans end via end, which
+// flags unexpected failures but treats ErrNotFound and ErrConflict as
+// normal outcomes.
+func (t *Traced) start(ctx context.Context, op string) (context.Context, trace.Span) {
+	return telemetry.StartSpan(ctx, "store."+op, attribute.String("db.operation", op))
+}
+
+func end(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrConflict) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+func (t *Traced) Get(ctx context.Context, id int) (models.User, error) {
+	ctx, span := t.start(ctx, "Get")
+	u, err := t.inner.Get(ctx, id)
+	end(span, err)
+	return u, err
+}
+
+func (t *Traced) GetByUUID(ctx context.Context, uuid string) (models.User, error) {
+	ctx, span := t.start(ctx, "GetByUUID")
+	u, err := t.inner.GetByUUID(ctx, uuid)
+	end(span, err)
+	return u, err
+}
+
+func (t *Traced) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	ctx, span := t.start(ctx, "GetByEmail")
+	u, err := t.inner.GetByEmail(ctx, email)
+	end(span, err)
+	return u, err
+}
+
+func (t *Traced) List(ctx context.Context, opts ListOptions) ([]models.User, error) {
+	ctx, span := t.start(ctx, "List")
+	all, err := t.inner.List(ctx, opts)
+	end(span, err)
+	return all, err
+}
+
+func (t *Traced) Create(ctx context.Context, u models.User) (models.User, error) {
+	ctx, span := t.start(ctx, "Create")
+	created, err := t.inner.Create(ctx, u)
+	end(span, err)
+	return created, err
+}
+
+func (t *Traced) Update(ctx context.Context, u models.User) error {
+	ctx, span := t.start(ctx, "Update")
+	err := t.inner.Update(ctx, u)
+	end(span, err)
+	return err
+}
+
+func (t *Traced) Delete(ctx context.Context, id int) error {
+	ctx, span := t.start(ctx, "Delete")
+	err := t.inner.Delete(ctx, id)
+	end(span, err)
+	return err
+}
+
+func (t *Traced) Restore(ctx context.Context, id int) error {
+	ctx, span := t.start(ctx, "Restore")
+	err := t.inner.Restore(ctx, id)
+	end(span, err)
+	return err
+}
+
+// WithTx traces the transaction as one span and hands fn a traced
+// view of the transactional store, so the calls inside show up as
+// children.
+func (t *Traced) WithTx(ctx context.Context, fn func(Store) error) error {
+	ctx, span := t.start(ctx, "WithTx")
+	err := t.inner.WithTx(ctx, func(tx Store) error {
+		return fn(&Traced{inner: tx})
+	})
+	end(span, err)
+	return err
+}
+
+// Each streams through the inner store's Iterator when it has one.
+func (t *Traced) Each(ctx context.Context, opts ListOptions, fn func(models.User) error) error {
+	ctx, span := t.start(ctx, "Each")
+	err := Each(ctx, t.inner, opts, fn)
+	end(span, err)
+	return err
+}
+
+// Search uses the inner store's native Searcher when available, the
+// in-process scan otherwise.
+func (t *Traced) Search(ctx context.Context, query string, limit int) ([]models.User, error) {
+	ctx, span := t.start(ctx, "Search")
+	all, err := SearchUsers(ctx, t.inner, query, limit)
+	end(span, err)
+	return all, err
+}
+
+// Stats aggregates natively when the inner store can.
+func (t *Traced) Stats(ctx context.Context) (Stats, error) {
+	ctx, span := t.start(ctx, "Stats")
+	st, err := UserStats(ctx, t.inner)
+	end(span, err)
+	return st, err
+}
+
+// Purge forwards to the inner store's Purger; every shipped backend
+// has one, but a decorator cannot prove that statically.
+func (t *Traced) Purge(ctx context.Context, cutoff time.Time, dryRun bool) (int, error) {
+	p, ok := t.inner.(Purger)
+	if !ok {
+		return 0, errors.New("store: inner store does not support purge")
+	}
+	ctx, span := t.start(ctx, "Purge")
+	n, err := p.Purge(ctx, cutoff, dryRun)
+	end(span, err)
+	return n, err
+}
+
+// Close forwards to the inner store when it holds resources.
+func (t *Traced) Close() error {
+	if c, ok := t.inner.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// walEntry is one logged mutation. Deletes carry only the user ID.
+type walEntry struct {
+	Op   string      `json:"op"` // "create", "update", or "delete"
+	User models.User `json:"user"`
+}
+
+// wal is an append-only operation log giving the in-memory store
+// crash durability between snapshots. Appends happen while the store's
+// write lock is held, so the file needs no locking of its own.
+type wal struct {
+	path  string
+	f     *os.File
+	enc   *json.Encoder
+	fsync bool
+}
+
+func openWAL(path string, fsync bool) (*wal, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("store: opening wal %q: %w", path, err)
+	}
+	return &wal{path: path, f: f, enc: json.NewEncoder(f), fsync: fsync}, nil
+}
+
+func (w *wal) append(e walEntry) {
+	if err := w.enc.Encode(e); err != nil {
+		fmt.Fprintf(os.Stderr, "iris: wal append: %v\n", err)
+		return
+	}
+	if w.fsync {
+		if err := w.f.Sync(); err != nil {
+			fmt.Fprintf(os.Stderr, "iris: wal sync: %v\n", err)
+		}
+	}
+}
+
+// truncate discards the log; called after a snapshot has made its
+// contents redundant.
+func (w *wal) truncate() error {
+	if err := w.f.Truncate(0); err != nil {
+		return err
+	}
+	_, err := w.f.Seek(0, 0)
+	return err
+}
+
+func (w *wal) close() error { return w.f.Close() }
+
+// NewMemoryWithWAL returns a snapshotting Memory store that also
+// appends every mutation to an operation log at walPath. On startup
+// the snapshot is restored and the log replayed on top of it; the log
+// is compacted each time a snapshot is written.
+func NewMemoryWithWAL(path, walPath string, interval time.Duration) (*Memory, error) {
+	return NewMemoryWithPersistence(PersistConfig{
+		SnapshotPath: path,
+		WALPath:      walPath,
+		Interval:     interval,
+	})
+}
+
+// replayWAL applies logged operations on top of the loaded snapshot.
+func (m *Memory) replayWAL(walPath string) error {
+	f, err := os.Open(walPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("store: opening wal %q: %w", walPath, err)
+	}
+	defer f.Close()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var e walEntry
+		if err := json.Unmarshal(line, &e); err != nil {
+			// A torn final write after a crash is expected; stop there.
+			break
+		}
+		switch e.Op {
+		case "create", "update":
+			if old, ok := m.users[e.User.ID]; ok && old.Email != e.User.Email {
+				delete(m.byEmail, old.Email)
+			}
+			m.users[e.User.ID] = e.User
+			if e.User.UUID != "" {
+				m.byUUID[e.User.UUID] = e.User.ID
+			}
+			if e.User.DeletedAt == nil {
+				m.byEmail[e.User.Email] = e.User.ID
+			} else {
+				delete(m.byEmail, e.User.Email)
+			}
+		case "delete":
+			if old, ok := m.users[e.User.ID]; ok {
+				delete(m.byUUID, old.UUID)
+				delete(m.byEmail, old.Email)
+			}
+			delete(m.users, e.User.ID)
+		}
+		m.bumpNextID(e.User.ID)
+	}
+	m.publishLocked()
+	return sc.Err()
+}
@@ -0,0 +1,76 @@
+// Package timing accumulates named per-stage durations for a single
+// request and renders them as a Server-Timing header value, so browser
+// devtools can show where a response's latency went.
+package timing
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type ctxKey struct{}
+
+// Recorder collects stage durations for one request. Repeated stages
+// (several store calls while assembling one response) accumulate under
+// the same name. Safe for concurrent use; /batch runs items in
+// parallel.
+type Recorder struct {
+	mu     sync.Mutex
+	order  []string
+	totals map[string]time.Duration
+}
+
+func New() *Recorder {
+	return &Recorder{totals: map[string]time.Duration{}}
+}
+
+// NewContext returns ctx carrying rec, so instrumented code anywhere
+// downstream can record into it.
+func NewContext(ctx context.Context, rec *Recorder) context.Context {
+	return context.WithValue(ctx, ctxKey{}, rec)
+}
+
+// Stage starts timing the named stage and returns the func that stops
+// it. With no Recorder in ctx both halves are no-ops, so callers
+// instrument unconditionally:
+//
+//	defer timing.Stage(ctx, "store")()
+func Stage(ctx context.Context, name string) func() {
+	rec, ok := ctx.Value(ctxKey{}).(*Recorder)
+	if !ok {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { rec.Add(name, time.Since(start)) }
+}
+
+// Add records d against the named stage.
+func (r *Recorder) Add(name string, d time.Duration) {
+	r.mu.Lock()
+	if _, ok := r.totals[name]; !ok {
+		r.order = append(r.order, name)
+	}
+	r.totals[name] += d
+	r.mu.Unlock()
+}
+
+// Header renders the collected stages in Server-Timing form — e.g.
+// "auth;dur=0.4, store;dur=2.1" — in the order they first appeared.
+// Empty when nothing was recorded.
+func (r *Recorder) Header() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var b strings.Builder
+	for i, name := range r.order {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(name)
+		b.WriteString(";dur=")
+		b.WriteString(strconv.FormatFloat(float64(r.totals[name])/float64(time.Millisecond), 'f', 1, 64))
+	}
+	return b.String()
+}
@@ -0,0 +1,253 @@
+// Package webhook delivers signed user lifecycle events to registered
+// HTTP endpoints. Deliveries are asynchronous with retries; recent
+// outcomes are kept so operators can see what reached whom.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Event is one lifecycle notification, serialized as the POST body.
+type Event struct {
+	ID   string    `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data"`
+}
+
+// Endpoint is a registered delivery target. Secret signs the payload
+// and is never reported back through List.
+type Endpoint struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Delivery records one attempt series for one endpoint and event.
+type Delivery struct {
+	ID         int       `json:"id"`
+	EndpointID int       `json:"endpoint_id"`
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	Attempts   int       `json:"attempts"`
+	Status     string    `json:"status"` // "pending", "delivered", or "failed"
+	LastError  string    `json:"last_error,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// maxAttempts bounds the retry series; backoff doubles from
+// retryBase between attempts.
+const (
+	maxAttempts   = 5
+	retryBase     = time.Second
+	maxDeliveries = 1000
+)
+
+// Dispatcher fans events out to every registered endpoint.
+type Dispatcher struct {
+	client *http.Client
+	newID  func() string
+
+	mu         sync.Mutex
+	endpoints  map[int]*Endpoint
+	nextEP     int
+	deliveries []*Delivery
+	nextDel    int
+
+	queue chan job
+	done  chan struct{}
+}
+
+type job struct {
+	endpoint Endpoint
+	delivery *Delivery
+	body     []byte
+	event    Event
+}
+
+// New returns a running Dispatcher. newID mints event IDs (typically
+// the store's UUID helper).
+func New(newID func() string) *Dispatcher {
+	d := &Dispatcher{
+		client:    &http.Client{Timeout: 10 * time.Second},
+		newID:     newID,
+		endpoints: map[int]*Endpoint{},
+		queue:     make(chan job, 256),
+		done:      make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Close stops the delivery worker. Queued jobs are dropped.
+func (d *Dispatcher) Close() {
+	close(d.done)
+}
+
+// SetTransport swaps the delivery client's transport, letting the
+// caller layer in tracing or proxies. Call before the first event is
+// emitted.
+func (d *Dispatcher) SetTransport(rt http.RoundTripper) {
+	d.client.Transport = rt
+}
+
+// Register adds a delivery target and returns it with its ID set.
+func (d *Dispatcher) Register(url, secret string) Endpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.nextEP++
+	ep := &Endpoint{ID: d.nextEP, URL: url, Secret: secret, CreatedAt: time.Now().UTC()}
+	d.endpoints[ep.ID] = ep
+	return *ep
+}
+
+// Remove deletes an endpoint, reporting whether it existed.
+func (d *Dispatcher) Remove(id int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	_, ok := d.endpoints[id]
+	delete(d.endpoints, id)
+	return ok
+}
+
+// Endpoints lists registered targets ordered by ID.
+func (d *Dispatcher) Endpoints() []Endpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]Endpoint, 0, len(d.endpoints))
+	for i := 1; i <= d.nextEP; i++ {
+		if ep, ok := d.endpoints[i]; ok {
+			out = append(out, *ep)
+		}
+	}
+	return out
+}
+
+// Deliveries returns recent delivery records, oldest first.
+func (d *Dispatcher) Deliveries() []Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]Delivery, len(d.deliveries))
+	for i, del := range d.deliveries {
+		out[i] = *del
+	}
+	return out
+}
+
+// Emit queues one event for every registered endpoint. It never
+// blocks the caller: with a full queue the delivery is recorded as
+// failed immediately.
+func (d *Dispatcher) Emit(eventType string, data any) {
+	ev := Event{ID: d.newID(), Type: eventType, Time: time.Now().UTC(), Data: data}
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, ep := range d.endpoints {
+		d.nextDel++
+		del := &Delivery{
+			ID:         d.nextDel,
+			EndpointID: ep.ID,
+			EventID:    ev.ID,
+			EventType:  ev.Type,
+			Status:     "pending",
+			UpdatedAt:  time.Now().UTC(),
+		}
+		d.deliveries = append(d.deliveries, del)
+		if len(d.deliveries) > maxDeliveries {
+			d.deliveries = d.deliveries[len(d.deliveries)-maxDeliveries:]
+		}
+		select {
+		case d.queue <- job{endpoint: *ep, delivery: del, body: body, event: ev}:
+		default:
+			del.Status = "failed"
+			del.LastError = "delivery queue full"
+		}
+	}
+}
+
+// Sign computes the hex HMAC-SHA256 signature carried in the
+// X-Iris-Signature header, exported so receivers can share the code.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Dispatcher) run() {
+	for {
+		select {
+		case <-d.done:
+			return
+		case j := <-d.queue:
+			d.deliver(j)
+		}
+	}
+}
+
+// deliver runs the retry series for one endpoint, recording the
+// outcome on the delivery record.
+func (d *Dispatcher) deliver(j job) {
+	var lastErr error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-d.done:
+				return
+			case <-time.After(retryBase << (attempt - 2)):
+			}
+		}
+		lastErr = d.post(j)
+		d.mu.Lock()
+		j.delivery.Attempts = attempt
+		j.delivery.UpdatedAt = time.Now().UTC()
+		if lastErr == nil {
+			j.delivery.Status = "delivered"
+			j.delivery.LastError = ""
+		} else {
+			j.delivery.LastError = lastErr.Error()
+		}
+		d.mu.Unlock()
+		if lastErr == nil {
+			return
+		}
+	}
+	d.mu.Lock()
+	j.delivery.Status = "failed"
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) post(j job) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.endpoint.URL, bytes.NewReader(j.body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Iris-Event", j.event.Type)
+	req.Header.Set("X-Iris-Delivery", fmt.Sprintf("%d", j.delivery.ID))
+	req.Header.Set("X-Iris-Signature", Sign(j.endpoint.Secret, j.body))
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint answered %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// link is one hypermedia pointer in a _links section.
+type link struct {
+	Href   string `json:"href"`
+	Method string `json:"method,omitempty"`
+}
+
+// linkedUser is the wire shape of a user resource: the scrubbed model
+// plus its _links section.
+type linkedUser struct {
+	models.User
+	Links map[string]link `json:"_links"`
+}
+
+// userCollection is the wire shape of a user listing: the page of
+// resources plus pagination links.
+type userCollection struct {
+	Items []linkedUser    `json:"items"`
+	Links map[string]link `json:"_links"`
+}
+
+// linkBase resolves the absolute prefix links are built against:
+// IRIS_BASE_URL when configured, otherwise the forwarded scheme and
+// host when the peer is a trusted proxy, otherwise the request's own.
+// The version mount point (/v1) survives StripPrefix via RequestURI.
+func linkBase(r *http.Request) string {
+	base := os.Getenv("IRIS_BASE_URL")
+	if base == "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		host := r.Host
+		if peerIsTrustedProxy(r) {
+			if v := r.Header.Get("X-Forwarded-Proto"); v != "" {
+				scheme = v
+			}
+			if v := r.Header.Get("X-Forwarded-Host"); v != "" {
+				host = v
+			}
+		}
+		base = scheme + "://" + host
+	}
+	orig, _, _ := strings.Cut(r.RequestURI, "?")
+	prefix := strings.TrimSuffix(orig, r.URL.Path)
+	return strings.TrimSuffix(base, "/") + prefix
+}
+
+func peerIsTrustedProxy(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer := net.ParseIP(host)
+	return peer != nil && isTrustedProxy(peer)
+}
+
+// withUserLinks wraps an already-scrubbed user with its _links.
+func withUserLinks(r *http.Request, u models.User) linkedUser {
+	base := linkBase(r)
+	self := base + "/users/" + strconv.Itoa(u.ID)
+	return linkedUser{User: u, Links: map[string]link{
+		"self":       {Href: self},
+		"collection": {Href: base + "/users"},
+		"update":     {Href: self, Method: http.MethodPut},
+		"delete":     {Href: self, Method: http.MethodDelete},
+	}}
+}
+
+// collectionLinks builds self/next/prev for a listing, carrying every
+// other query parameter through unchanged.
+func collectionLinks(r *http.Request, total, limit, offset int) map[string]link {
+	base := linkBase(r) + "/users"
+	pageLink := func(offset int) link {
+		q := r.URL.Query()
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(offset))
+		return link{Href: base + "?" + q.Encode()}
+	}
+	links := map[string]link{"self": {Href: base + pageQuery(r)}}
+	if limit > 0 && offset+limit < total {
+		links["next"] = pageLink(offset + limit)
+	}
+	if offset > 0 {
+		links["prev"] = pageLink(max(offset-limit, 0))
+	}
+	return links
+}
+
+// setLinkHeader mirrors the pagination links as an RFC 5988 Link
+// header, in stable rel order, so generic clients can paginate
+// without parsing the body envelope.
+func setLinkHeader(w http.ResponseWriter, links map[string]link) {
+	var parts []string
+	for _, rel := range []string{"prev", "next"} {
+		if l, ok := links[rel]; ok {
+			parts = append(parts, "<"+l.Href+`>; rel="`+rel+`"`)
+		}
+	}
+	if len(parts) > 0 {
+		w.Header().Set("Link", strings.Join(parts, ", "))
+	}
+}
+
+// pageQuery renders the request's query string for the self link.
+func pageQuery(r *http.Request) string {
+	if enc := url.Values(r.URL.Query()).Encode(); enc != "" {
+		return "?" + enc
+	}
+	return ""
+}
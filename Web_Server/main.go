@@ -0,0 +1,773 @@
+// Command iris is the backend companion to the Iris web experience: a
+// small HTTP service that stores users for the interactive frontend.
+package main
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/auth"
+	"github.com/divijg19/Iris/Web_Server/internal/avatar"
+	"github.com/divijg19/Iris/Web_Server/internal/blob"
+	"github.com/divijg19/Iris/Web_Server/internal/config"
+	"github.com/divijg19/Iris/Web_Server/internal/group"
+	"github.com/divijg19/Iris/Web_Server/internal/redact"
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+	"github.com/divijg19/Iris/Web_Server/internal/telemetry"
+	"github.com/divijg19/Iris/Web_Server/internal/webhook"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// users is the repository the HTTP handlers operate on. It is wired
+// up in main and can be any store.Store implementation.
+var users store.Store
+
+var (
+	blobs   blob.Store
+	avatars *avatar.Processor
+)
+
+// newUserStore picks the user store backend from IRIS_STORE
+// ("memory" or "sqlite"); IRIS_STORE_DSN points sqlite at its
+// database file.
+func newUserStore() (store.Store, error) {
+	switch backend := os.Getenv("IRIS_STORE"); backend {
+	case "", "memory":
+		if path := os.Getenv("IRIS_SNAPSHOT"); path != "" {
+			cfg := store.PersistConfig{
+				SnapshotPath: path,
+				WALPath:      os.Getenv("IRIS_WAL"),
+				OnChange:     os.Getenv("IRIS_SNAPSHOT_ON_CHANGE") == "true",
+				Fsync:        os.Getenv("IRIS_SNAPSHOT_FSYNC") == "true",
+			}
+			if v := os.Getenv("IRIS_SNAPSHOT_INTERVAL"); v != "" {
+				d, err := time.ParseDuration(v)
+				if err != nil || d <= 0 {
+					return nil, fmt.Errorf("invalid IRIS_SNAPSHOT_INTERVAL %q", v)
+				}
+				cfg.Interval = d
+			}
+			if v := os.Getenv("IRIS_SNAPSHOT_DEBOUNCE"); v != "" {
+				d, err := time.ParseDuration(v)
+				if err != nil || d <= 0 {
+					return nil, fmt.Errorf("invalid IRIS_SNAPSHOT_DEBOUNCE %q", v)
+				}
+				cfg.Debounce = d
+			}
+			return store.NewMemoryWithPersistence(cfg)
+		}
+		return store.NewMemory(), nil
+	case "sqlite":
+		dsn := os.Getenv("IRIS_STORE_DSN")
+		if dsn == "" {
+			dsn = "data/iris.db"
+		}
+		return store.NewSQLite(dsn)
+	case "bolt":
+		dsn := os.Getenv("IRIS_STORE_DSN")
+		if dsn == "" {
+			dsn = "data/iris.bolt"
+		}
+		return store.NewBolt(dsn)
+	case "mongo":
+		db := os.Getenv("IRIS_MONGO_DB")
+		if db == "" {
+			db = "iris"
+		}
+		return store.NewMongo(context.Background(), os.Getenv("IRIS_STORE_DSN"), db)
+	case "mysql":
+		primary, err := store.NewMySQL(context.Background(), os.Getenv("IRIS_STORE_DSN"))
+		if err != nil {
+			return nil, err
+		}
+		return withReplicas(primary, func(dsn string) (store.Store, error) {
+			return store.NewMySQL(context.Background(), dsn)
+		})
+	case "postgres":
+		var maxConns int
+		if v := os.Getenv("IRIS_PG_MAX_CONNS"); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid IRIS_PG_MAX_CONNS %q", v)
+			}
+			maxConns = n
+		}
+		primary, err := store.NewPostgres(context.Background(), os.Getenv("IRIS_STORE_DSN"), int32(maxConns))
+		if err != nil {
+			return nil, err
+		}
+		return withReplicas(primary, func(dsn string) (store.Store, error) {
+			return store.NewPostgres(context.Background(), dsn, int32(maxConns))
+		})
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", backend)
+	}
+}
+
+// withReplicas wraps a SQL primary with the read replicas listed in
+// IRIS_STORE_REPLICA_DSNS (comma-separated), each opened by the same
+// constructor as the primary. Without replicas the primary is returned
+// as-is.
+func withReplicas(primary store.Store, open func(dsn string) (store.Store, error)) (store.Store, error) {
+	raw := os.Getenv("IRIS_STORE_REPLICA_DSNS")
+	if raw == "" {
+		return primary, nil
+	}
+	var replicas []store.Store
+	for _, dsn := range strings.Split(raw, ",") {
+		dsn = strings.TrimSpace(dsn)
+		if dsn == "" {
+			continue
+		}
+		rep, err := open(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("opening replica: %w", err)
+		}
+		replicas = append(replicas, rep)
+	}
+	return store.NewReplicated(primary, replicas...), nil
+}
+
+// newBlobStore picks the blob backend: S3/MinIO when IRIS_S3_ENDPOINT
+// is set, otherwise a local directory.
+func newBlobStore() (blob.Store, error) {
+	if endpoint := os.Getenv("IRIS_S3_ENDPOINT"); endpoint != "" {
+		return blob.NewS3Store(context.Background(), blob.S3Config{
+			Endpoint:  endpoint,
+			AccessKey: os.Getenv("IRIS_S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("IRIS_S3_SECRET_KEY"),
+			Bucket:    os.Getenv("IRIS_S3_BUCKET"),
+			Prefix:    os.Getenv("IRIS_S3_PREFIX"),
+			Region:    os.Getenv("IRIS_S3_REGION"),
+			UseSSL:    os.Getenv("IRIS_S3_INSECURE") == "",
+		})
+	}
+	return blob.NewFSStore("data/blobs")
+}
+
+func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "demo":
+			runConcurrencyDemo()
+			return
+		case "benchstore":
+			runBenchStore(os.Args[2:])
+			return
+		case "migrate":
+			runMigrate(os.Args[2:])
+			return
+		case "config":
+			runConfigCmd(os.Args[2:])
+			return
+		}
+	}
+
+	seedPath := flag.String("seed", "", "JSON file of users loaded at startup (existing emails skipped)")
+	configPath := flag.String("config", os.Getenv("IRIS_CONFIG"), "config file ('key: value' lines)")
+	addrFlag := flag.String("addr", "", "listen address (overrides IRIS_ADDR)")
+	flag.Parse()
+
+	// Resolve the layered configuration first: defaults, then the file,
+	// then real IRIS_* environment variables, with flags on top. Load
+	// pushes the merged view into the environment, so everything below
+	// keeps reading os.Getenv unchanged.
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *addrFlag != "" {
+		cfg.Set("addr", *addrFlag)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	shutdownTracing, err := telemetry.Setup(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	users, err = newUserStore()
+	if err != nil {
+		log.Fatal(err)
+	}
+	users, err = newUserCache(users)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if telemetry.Enabled() {
+		users = store.NewTraced(users)
+	}
+	if serverTimingEnabled() {
+		users = store.NewStopwatch(users)
+	}
+	if *seedPath != "" {
+		n, err := loadSeed(context.Background(), users, *seedPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("iris: seeded %d users from %s", n, *seedPath)
+	}
+
+	// The middleware's 401/403s go through the same problem+json
+	// renderer as every handler error.
+	auth.Error = apiError
+	sessions, err = newSessions()
+	if err != nil {
+		log.Fatal(err)
+	}
+	authn, err = newAuthenticator()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if authn != nil {
+		refreshTTL := time.Duration(0)
+		if v := os.Getenv("IRIS_REFRESH_TTL"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d <= 0 {
+				log.Fatalf("invalid IRIS_REFRESH_TTL %q", v)
+			}
+			refreshTTL = d
+		}
+		refreshes = auth.NewRefreshStore(refreshTTL)
+	}
+
+	startRetentionJob(users)
+
+	// Background workers tied to the process lifetime stop when main
+	// returns.
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	if err := startCacheJanitors(workerCtx); err != nil {
+		log.Fatal(err)
+	}
+
+	// The remaining independent startup work — the blob backend, OAuth
+	// provider discovery, and the cache warm-up — runs as one group:
+	// nothing orders the three among themselves, and the first failure
+	// cancels the rest and aborts startup with that error instead of a
+	// partially started server.
+	g, _ := group.WithContext(context.Background())
+	g.Go(func() error {
+		var err error
+		blobs, err = newBlobStore()
+		return err
+	})
+	g.Go(newOAuthProviders)
+	g.Go(warmUserCache)
+	if err := g.Wait(); err != nil {
+		log.Fatal(err)
+	}
+	avatars = avatar.NewProcessor(blobs, 4, 64)
+	defer avatars.Close()
+	// The state cache is tiny, but its eviction count under
+	// /admin/debug/vars is the canary for someone spraying oauth
+	// starts.
+	expvar.Publish("oauth_state_evictions", expvar.Func(func() any { return oauthStates.Evictions() }))
+	webhooks = webhook.New(store.NewUUID)
+	defer webhooks.Close()
+	if telemetry.Enabled() {
+		webhooks.SetTransport(telemetry.Transport(nil))
+	}
+	if err := applyBodyLimitOverrides(); err != nil {
+		log.Fatal(err)
+	}
+	if err := applyCachePolicyOverrides(); err != nil {
+		log.Fatal(err)
+	}
+	if err := applyRouteTimeoutOverrides(); err != nil {
+		log.Fatal(err)
+	}
+	if err := applySanitizerConfig(); err != nil {
+		log.Fatal(err)
+	}
+	if err := applyInflightLimits(); err != nil {
+		log.Fatal(err)
+	}
+
+	limiter, err = newRateLimiter()
+	if err != nil {
+		log.Fatal(err)
+	}
+	accessLog, err = newAccessLogger()
+	if err != nil {
+		log.Fatal(err)
+	}
+	proxyRoutes, err = newProxyRoutes()
+	if err != nil {
+		log.Fatal(err)
+	}
+	handler := withAccessLog(accessLog, withRecovery(telemetry.Middleware(withCompression(withServerTiming(withRateLimit(limiter, withInflightLimit(withMethodOverride(mountVersions()))))))))
+
+	watchConfig(cfg, *configPath)
+	if err := serve(handler); err != nil {
+		log.Fatal(err)
+	}
+	// A nil return means the drain finished; flush and close the
+	// repository (the memory backend writes its final snapshot here)
+	// before the deferred worker shutdowns run.
+	if c, ok := users.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			log.Printf("iris: closing store: %v", err)
+		}
+	}
+	// Flush whatever spans are still buffered before the process exits.
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		log.Printf("iris: flushing traces: %v", err)
+	}
+	log.Println("iris: shutdown complete")
+}
+
+// apiV1 builds the route table for the current API version. Routes
+// hang off groups that carry the shared prefix and middleware; the
+// *By keys still reference the wrapper tables in bodylimit.go,
+// timeout.go, and cachepolicy.go.
+func apiV1() *http.ServeMux {
+	rt := newRouter()
+
+	authGroup := rt.Group("/auth", timedBy("/auth/"), limitedBy("/auth/"))
+	authGroup.Handle("/login", loginUser)
+	authGroup.Handle("/refresh", refreshAuth)
+	authGroup.Handle("/logout", logoutUser)
+	// OAuth routes manage their own redirects and callbacks, outside
+	// the shared auth stack.
+	rt.Handle("/auth/oauth/", oauthRoutes)
+
+	userGroup := rt.Group("/users")
+	userGroup.Handle("", usersCollection, cachedBy("/users"), timedBy("/users"), limitedBy("/users"), protected)
+	userGroup.Handle("/search", searchUsers, scoped("users:read"))
+	userGroup.Handle("/stats", userStats, cachedBy("/users/stats"), scoped("users:read"))
+	userGroup.Handle("/import", importUsers, guardedBy("/users/import"), timedBy("/users/import"), limitedBy("/users/import"), scoped("users:write"))
+	userGroup.Handle("/export", exportUsers, guardedBy("/users/export"), timedBy("/users/export"), scoped("users:read"))
+	userGroup.Handle("/", userRoutes, cachedBy("/users/"), timedBy("/users/"), limitedBy("/users/"), protected)
+
+	adminGroup := rt.Group("/admin", scoped("admin"))
+	adminGroup.Handle("/audit", adminAudit, timedBy("/admin/"))
+	adminGroup.Handle("/cache/stats", adminCacheStats, timedBy("/admin/"))
+	adminGroup.Handle("/cache/ttl", adminCacheTTL, timedBy("/admin/"))
+	adminGroup.Handle("/cache/flush", adminCacheFlush, timedBy("/admin/"))
+	adminGroup.Handle("/cache/keys", adminCacheKeys, timedBy("/admin/"))
+	adminGroup.Handle("/cache/entry", adminCacheEntry, timedBy("/admin/"))
+	adminGroup.Handle("/webhooks", adminWebhooks, limitedBy("/admin/"))
+	adminGroup.Handle("/webhooks/", adminWebhookRoutes)
+	adminGroup.Handle("/debug/", debugRoutes)
+
+	rt.Handle("/openapi.json", serveOpenAPI, cachedBy("/openapi.json"))
+	rt.Handle("/docs", serveDocs, cachedBy("/docs"))
+	rt.Handle("/batch", batchHandler(rt.Mux()), guardedBy("/batch"), timedBy("/batch"), limitedBy("/batch"))
+	rt.NotFound()
+	return rt.Mux()
+}
+
+// apiVersions maps URL prefixes to API version route tables. A
+// breaking /v2 mounts here beside /v1 without disturbing it.
+var apiVersions = map[string]func() *http.ServeMux{
+	"/v1": apiV1,
+}
+
+// mountVersions assembles the root handler: every registered version
+// under its prefix, plus the bare paths as an alias for the newest
+// version so pre-versioning clients keep working.
+func mountVersions() *http.ServeMux {
+	root := http.NewServeMux()
+	var newest string
+	for prefix, build := range apiVersions {
+		root.Handle(prefix+"/", http.StripPrefix(prefix, withMethodNegotiation(build())))
+		if prefix > newest {
+			newest = prefix
+		}
+	}
+	for _, p := range proxyRoutes {
+		root.Handle(p.prefix+"/", p.handler())
+		log.Printf("iris: proxying %s/ to %s", p.prefix, p.target)
+	}
+	// The unversioned paths predate the /v1 mount and only survive as
+	// an alias; advertise their retirement so clients migrate. Paths
+	// the API does not claim fall through to the embedded frontend.
+	root.Handle("/", staticOrAPI(deprecated("unversioned API paths",
+		time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2027, time.March, 1, 0, 0, 0, 0, time.UTC),
+		withMethodNegotiation(apiVersions[newest]()))))
+	return root
+}
+
+// listOptionsFromQuery translates the collection query parameters —
+// include_deleted, name_contains, email_contains, age_gte, age_lte,
+// and sort ("name,-age") — into repository list options.
+func listOptionsFromQuery(r *http.Request) (store.ListOptions, error) {
+	q := r.URL.Query()
+	opts := store.ListOptions{
+		IncludeDeleted: q.Get("include_deleted") == "true",
+		NameContains:   q.Get("name_contains"),
+		EmailContains:  q.Get("email_contains"),
+	}
+	for param, dst := range map[string]**int{"age_gte": &opts.AgeMin, "age_lte": &opts.AgeMax} {
+		if v := q.Get(param); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return store.ListOptions{}, fmt.Errorf("invalid %s", param)
+			}
+			*dst = &n
+		}
+	}
+	keys, err := store.ParseSort(q.Get("sort"))
+	if err != nil {
+		return store.ListOptions{}, errors.New("invalid sort field")
+	}
+	opts.Sort = keys
+	return opts, nil
+}
+
+// usersCollection dispatches methods on the /users collection.
+func usersCollection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		requireScope("users:read", listUsers)(w, r)
+	case http.MethodPost:
+		requireScope("users:write", createUser)(w, r)
+	case http.MethodDelete:
+		requireScope("users:write", bulkDeleteUsers)(w, r)
+	default:
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// listUsers returns a page of users ordered by ID. Pagination uses
+// limit/offset query parameters; the total (pre-pagination) count is
+// reported in X-Total-Count.
+func listUsers(w http.ResponseWriter, r *http.Request) {
+	limit, offset := 50, 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			apiError(w, r, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+	if v := r.URL.Query().Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			apiError(w, r, http.StatusBadRequest, "invalid offset")
+			return
+		}
+		offset = n
+	}
+
+	opts, err := listOptionsFromQuery(r)
+	if err != nil {
+		apiError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	all, err := users.List(r.Context(), opts)
+	if err != nil {
+		apiStoreError(w, r, err)
+		return
+	}
+
+	total := len(all)
+	if offset > total {
+		offset = total
+	}
+	page := all[offset:]
+	if limit < len(page) {
+		page = page[:limit]
+	}
+	items := make([]linkedUser, len(page))
+	for i, u := range page {
+		items[i] = withUserLinks(r, scrubUser(u))
+	}
+
+	links := collectionLinks(r, total, limit, offset)
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	setLinkHeader(w, links)
+	respond.JSON(w, http.StatusOK, userCollection{Items: items, Links: links})
+}
+
+func createUser(w http.ResponseWriter, r *http.Request) {
+	var u models.User
+	if !decodeJSON(w, r, &u) {
+		return
+	}
+	u = sanitizeUser(u)
+	if u.Password != "" {
+		hash, err := hashPassword(u.Password)
+		if err != nil {
+			apiError(w, r, http.StatusInternalServerError, "internal error")
+			return
+		}
+		u.PasswordHash = hash
+		u.Password = ""
+	}
+
+	created, err := users.Create(r.Context(), u)
+	if errors.Is(err, store.ErrConflict) {
+		apiError(w, r, http.StatusConflict, "email already in use")
+		return
+	}
+	if err != nil {
+		log.Printf("createUser: %s", redact.Error(err))
+		apiError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	recordAudit(r, "create", created.ID, userDiff(models.User{}, created))
+	emitUserEvent("user.created", created)
+	respond.Created(w, linkBase(r)+"/users/"+strconv.Itoa(created.ID), withUserLinks(r, scrubUser(created)))
+}
+
+// userRoutes dispatches /users/{id} and the avatar sub-resources.
+func userRoutes(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/users/")
+	parts := strings.Split(rest, "/")
+	if parts[0] == "by-email" {
+		if len(parts) != 2 || r.Method != http.MethodGet {
+			apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		requireScope("users:read", func(w http.ResponseWriter, r *http.Request) {
+			getUserByEmail(w, r, parts[1])
+		})(w, r)
+		return
+	}
+	if len(parts) >= 2 {
+		id, err := strconv.Atoi(parts[0])
+		if err != nil {
+			apiError(w, r, http.StatusBadRequest, "invalid user id")
+			return
+		}
+		switch {
+		case parts[1] == "avatar" && r.Method == http.MethodPost && len(parts) == 2:
+			requireScope("users:write", func(w http.ResponseWriter, r *http.Request) {
+				uploadAvatar(w, r, id)
+			})(w, r)
+		case parts[1] == "avatar" && r.Method == http.MethodGet && len(parts) == 3:
+			requireScope("users:read", func(w http.ResponseWriter, r *http.Request) {
+				serveAvatar(w, r, id, parts[2])
+			})(w, r)
+		case parts[1] == "restore" && r.Method == http.MethodPost && len(parts) == 2:
+			requireScope("users:write", func(w http.ResponseWriter, r *http.Request) {
+				restoreUser(w, r, id)
+			})(w, r)
+		default:
+			apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		}
+		return
+	}
+	userResource(w, r)
+}
+
+// searchUsers serves GET /users/search?q=: case-insensitive prefix,
+// substring, and trigram fuzzy matching over names and emails.
+func searchUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		apiError(w, r, http.StatusBadRequest, "q is required")
+		return
+	}
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			apiError(w, r, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+	results, err := store.SearchUsers(r.Context(), users, q, limit)
+	if err != nil {
+		apiStoreError(w, r, err)
+		return
+	}
+	for i := range results {
+		results[i] = scrubUser(results[i])
+	}
+	if results == nil {
+		results = []models.User{}
+	}
+	respond.JSON(w, http.StatusOK, results)
+}
+
+// getUserByEmail serves GET /users/by-email/{email} off the email
+// index, for clients that know the address but not the ID.
+func getUserByEmail(w http.ResponseWriter, r *http.Request, email string) {
+	u, err := users.GetByEmail(r.Context(), email)
+	if errors.Is(err, store.ErrNotFound) {
+		apiError(w, r, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		apiStoreError(w, r, err)
+		return
+	}
+	respond.JSON(w, http.StatusOK, withUserLinks(r, scrubUser(u)))
+}
+
+// restoreUser undoes a soft delete via POST /users/{id}/restore.
+func restoreUser(w http.ResponseWriter, r *http.Request, id int) {
+	err := users.Restore(r.Context(), id)
+	if errors.Is(err, store.ErrNotFound) {
+		apiError(w, r, http.StatusNotFound, "no deleted user with that id")
+		return
+	}
+	if err != nil {
+		apiStoreError(w, r, err)
+		return
+	}
+	u, err := users.Get(r.Context(), id)
+	if err != nil {
+		apiStoreError(w, r, err)
+		return
+	}
+	recordAudit(r, "restore", id, nil)
+	emitUserEvent("user.updated", u)
+	respond.JSON(w, http.StatusOK, withUserLinks(r, scrubUser(u)))
+}
+
+// uploadAvatar accepts a raw image body and enqueues it for resizing.
+func uploadAvatar(w http.ResponseWriter, r *http.Request, id int) {
+	if _, err := users.Get(r.Context(), id); err != nil {
+		if errors.Is(err, store.ErrNotFound) {
+			apiError(w, r, http.StatusNotFound, "user not found")
+		} else {
+			apiError(w, r, http.StatusInternalServerError, "internal error")
+		}
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 8<<20))
+	if err != nil {
+		apiError(w, r, http.StatusRequestEntityTooLarge, "image too large")
+		return
+	}
+	if err := avatars.Enqueue(id, data); err != nil {
+		apiError(w, r, http.StatusServiceUnavailable, "try again later")
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// serveAvatar streams one stored variant ("thumb", "medium", or
+// "original") from the blob store.
+func serveAvatar(w http.ResponseWriter, r *http.Request, id int, variant string) {
+	if _, ok := avatar.Variants[variant]; !ok && variant != "original" {
+		apiError(w, r, http.StatusNotFound, "unknown avatar size")
+		return
+	}
+	rc, err := blobs.Get(r.Context(), avatar.Key(id, variant))
+	if err == blob.ErrNotFound {
+		apiError(w, r, http.StatusNotFound, "avatar not found")
+		return
+	}
+	if err != nil {
+		apiError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	defer rc.Close()
+	w.Header().Set("Content-Type", "image/png")
+	io.Copy(w, rc)
+}
+
+// userResource handles GET/PUT/DELETE on a single user. Users are
+// addressable by integer ID or UUID, per installation preference.
+func userResource(w http.ResponseWriter, r *http.Request) {
+	scope := "users:read"
+	if r.Method != http.MethodGet {
+		scope = "users:write"
+	}
+	requireScope(scope, userResourceScoped)(w, r)
+}
+
+func userResourceScoped(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/users/")
+	var (
+		u   models.User
+		err error
+	)
+	if id, convErr := strconv.Atoi(key); convErr == nil {
+		u, err = users.Get(r.Context(), id)
+	} else {
+		u, err = users.GetByUUID(r.Context(), key)
+	}
+	if errors.Is(err, store.ErrNotFound) {
+		apiError(w, r, http.StatusNotFound, "user not found")
+		return
+	}
+	if err != nil {
+		apiStoreError(w, r, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		tag := userETag(u)
+		w.Header().Set("ETag", tag)
+		lm := userLastModified(u)
+		if !lm.IsZero() {
+			w.Header().Set("Last-Modified", lm.UTC().Format(http.TimeFormat))
+		}
+		if inm := r.Header.Get("If-None-Match"); inm != "" && etagMatch(inm, tag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if notModifiedSince(r, lm) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		respond.JSON(w, http.StatusOK, withUserLinks(r, scrubUser(u)))
+	case http.MethodPut:
+		if !checkPrecondition(w, r, u) {
+			return
+		}
+		var in models.User
+		if !decodeJSON(w, r, &in) {
+			return
+		}
+		in = sanitizeUser(in)
+		in.ID = u.ID
+		if err := users.Update(r.Context(), in); err != nil {
+			apiStoreError(w, r, err)
+			return
+		}
+		recordAudit(r, "update", u.ID, userDiff(u, in))
+		in.UUID = u.UUID
+		emitUserEvent("user.updated", in)
+		w.Header().Set("ETag", userETag(in))
+		respond.JSON(w, http.StatusOK, withUserLinks(r, scrubUser(in)))
+	case http.MethodPatch:
+		if !checkPrecondition(w, r, u) {
+			return
+		}
+		patchUser(w, r, u)
+	case http.MethodDelete:
+		if !checkPrecondition(w, r, u) {
+			return
+		}
+		if err := users.Delete(r.Context(), u.ID); err != nil {
+			apiStoreError(w, r, err)
+			return
+		}
+		recordAudit(r, "delete", u.ID, nil)
+		emitUserEvent("user.deleted", u)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// allowedMethods mirrors the apiV1 route table so the server can
+// answer OPTIONS and put an Allow header on 405s. It has to move in
+// lockstep with the mux registrations; nil means the path is unknown
+// and falls through to the mux (and its 404).
+func allowedMethods(path string) []string {
+	switch path {
+	case "/auth/login", "/auth/refresh", "/auth/logout":
+		return []string{http.MethodPost}
+	case "/users":
+		return []string{http.MethodGet, http.MethodPost, http.MethodDelete}
+	case "/users/search", "/users/stats", "/users/export":
+		return []string{http.MethodGet}
+	case "/users/import", "/batch":
+		return []string{http.MethodPost}
+	case "/admin/audit", "/admin/cache/stats", "/admin/cache/ttl", "/admin/cache/keys", "/admin/webhooks/deliveries":
+		return []string{http.MethodGet}
+	case "/admin/cache/flush":
+		return []string{http.MethodPost}
+	case "/admin/cache/entry":
+		return []string{http.MethodGet, http.MethodDelete}
+	case "/admin/webhooks":
+		return []string{http.MethodGet, http.MethodPost}
+	case "/openapi.json", "/docs":
+		return []string{http.MethodGet}
+	}
+	switch {
+	case strings.HasPrefix(path, "/admin/debug/"):
+		return []string{http.MethodGet}
+	case strings.HasPrefix(path, "/admin/webhooks/"):
+		return []string{http.MethodDelete}
+	case strings.HasPrefix(path, "/auth/oauth/"):
+		return []string{http.MethodGet}
+	case strings.HasPrefix(path, "/users/by-email/"):
+		return []string{http.MethodGet}
+	case strings.HasPrefix(path, "/users/") && strings.HasSuffix(path, "/avatar"):
+		return []string{http.MethodPost}
+	case strings.HasPrefix(path, "/users/") && strings.Contains(path, "/avatar/"):
+		return []string{http.MethodGet}
+	case strings.HasPrefix(path, "/users/") && strings.HasSuffix(path, "/restore"):
+		return []string{http.MethodPost}
+	case strings.HasPrefix(path, "/users/"):
+		return []string{http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	return nil
+}
+
+// withMethodNegotiation answers OPTIONS from the route table and turns
+// wrong-method requests into a 405 with an Allow header before they
+// reach a handler. Unknown paths pass through untouched so the mux
+// still owns 404.
+func withMethodNegotiation(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allow := allowedMethods(r.URL.Path)
+		if allow == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		header := strings.Join(append(slices.Clone(allow), http.MethodOptions), ", ")
+		if r.Method == http.MethodOptions {
+			w.Header().Set("Allow", header)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !slices.Contains(allow, r.Method) {
+			w.Header().Set("Allow", header)
+			respondMethodNotAllowed(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
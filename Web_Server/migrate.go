@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+
+	_ "github.com/go-sql-driver/mysql" // mysql database/sql driver
+	_ "github.com/jackc/pgx/v5/stdlib" // pgx database/sql driver
+	_ "modernc.org/sqlite"
+
+	"github.com/divijg19/Iris/Web_Server/internal/migrations"
+)
+
+// runMigrate implements "iris migrate [up|down|version]" against the
+// configured SQL backend, so schema changes can be applied separately
+// from serving (e.g. in a deploy step).
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dialect := fs.String("store", "sqlite", `SQL backend to migrate ("sqlite", "postgres", or "mysql")`)
+	dsn := fs.String("dsn", "", "database path (sqlite) or connection string (postgres, mysql)")
+	fs.Parse(args)
+
+	action := "up"
+	if fs.NArg() > 0 {
+		action = fs.Arg(0)
+	}
+
+	var (
+		driver string
+		source = *dsn
+	)
+	switch *dialect {
+	case "sqlite":
+		driver = "sqlite"
+		if source == "" {
+			source = "data/iris.db"
+		}
+	case "postgres":
+		driver = "pgx"
+		if source == "" {
+			log.Fatal("migrate: -dsn is required for postgres")
+		}
+	case "mysql":
+		driver = "mysql"
+		if source == "" {
+			log.Fatal("migrate: -dsn is required for mysql")
+		}
+	default:
+		log.Fatalf("migrate: unknown store %q", *dialect)
+	}
+
+	db, err := sql.Open(driver, source)
+	if err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	switch action {
+	case "up":
+		err = migrations.Migrate(ctx, db, *dialect)
+	case "down":
+		err = migrations.Rollback(ctx, db, *dialect)
+	case "version":
+		var v int
+		if v, err = migrations.Version(ctx, db); err == nil {
+			fmt.Println(v)
+		}
+	default:
+		log.Fatalf("migrate: unknown action %q (want up, down, or version)", action)
+	}
+	if err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// benchUser is a representative record: every field populated the way
+// a stored user would be, since omitted fields make encoding look
+// cheaper than it is in practice.
+var benchUser = User{
+	ID:           42,
+	UUID:         "018f2d5e-7c1a-7b3c-9d4e-0123456789ab",
+	Name:         "Ada Lovelace",
+	Email:        "ada@example.com",
+	Age:          36,
+	PasswordHash: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy",
+}
+
+// BenchmarkUserMarshal measures the encode half of every API response
+// and snapshot write.
+func BenchmarkUserMarshal(b *testing.B) {
+	u := benchUser
+	now := time.Now()
+	u.DeletedAt = &now
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(u); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkUserUnmarshal measures the decode half: request bodies,
+// snapshot restores, and WAL replay.
+func BenchmarkUserUnmarshal(b *testing.B) {
+	raw, err := json.Marshal(benchUser)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var u User
+		if err := json.Unmarshal(raw, &u); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Package models holds the data types shared by the Iris backend.
+package models
+
+import "time"
+
+// User is a single registered user of the Iris backend.
+//
+// Users carry two identifiers: a backend-assigned integer ID and a
+// UUIDv7. Which one clients address resources by is an installation
+// choice; handlers accept both in paths.
+type User struct {
+	ID    int    `json:"id" bson:"_id"`
+	UUID  string `json:"uuid,omitempty" bson:"uuid,omitempty"`
+	Name  string `json:"name" bson:"name"`
+	Email string `json:"email" bson:"email"`
+	Age   int    `json:"age" bson:"age"`
+
+	// Password carries a plaintext password on requests (create,
+	// login). It is hashed immediately and never stored or returned.
+	Password string `json:"password,omitempty" bson:"-"`
+
+	// PasswordHash is the bcrypt hash persisted by the stores. The
+	// json tag keeps it in snapshots and WAL entries; handlers scrub
+	// it before writing API responses.
+	PasswordHash string `json:"password_hash,omitempty" bson:"password_hash,omitempty"`
+
+	// DeletedAt marks a soft-deleted user. Deleted users are hidden
+	// from reads by default and can be restored until purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// clientCert is the identity a caller proved through mutual TLS.
+type clientCert struct {
+	CommonName string
+	DNSNames   []string
+}
+
+type clientCertKey struct{}
+
+// clientCertFrom returns the verified client certificate identity, if
+// the request arrived over mTLS.
+func clientCertFrom(ctx context.Context) (clientCert, bool) {
+	cc, ok := ctx.Value(clientCertKey{}).(clientCert)
+	return cc, ok
+}
+
+// clientCAPool loads the CA bundle from IRIS_TLS_CLIENT_CA, or nil
+// when mTLS is not configured.
+func clientCAPool() (*x509.CertPool, error) {
+	path := os.Getenv("IRIS_TLS_CLIENT_CA")
+	if path == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading IRIS_TLS_CLIENT_CA: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// withClientCert copies the verified peer certificate's CN and SANs
+// into the request context so handlers can tell services apart.
+func withClientCert(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			leaf := r.TLS.PeerCertificates[0]
+			cc := clientCert{CommonName: leaf.Subject.CommonName, DNSNames: leaf.DNSNames}
+			r = r.WithContext(context.WithValue(r.Context(), clientCertKey{}, cc))
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+
+	"github.com/divijg19/Iris/Web_Server/internal/cache"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// oauthProvider is one configured social login backend.
+type oauthProvider struct {
+	config *oauth2.Config
+
+	// userInfo fetches the authenticated user's email and display
+	// name using a token-bearing HTTP client.
+	userInfo func(ctx context.Context, client *http.Client) (email, name string, err error)
+}
+
+// oauthProviders maps provider path segments ("google", "github") to
+// their configuration; populated by newOAuthProviders at startup.
+var oauthProviders = map[string]*oauthProvider{}
+
+// oauthStates tracks outstanding state nonces so callbacks can only
+// complete flows this server started; a nonce not redeemed within ten
+// minutes simply expires, and the LRU bound keeps a client spraying
+// /auth/oauth/ starts from growing the map without limit.
+var oauthStates = func() *cache.Cache[string, struct{}] {
+	c := cache.New[string, struct{}]()
+	c.SetCapacity(10_000)
+	cache.Register("oauth_states", c.Stats)
+	cache.RegisterShards("oauth_states", c.ShardStats)
+	cache.RegisterTTL("oauth_states", c.TTL)
+	cache.RegisterAdmin("oauth_states", cache.NewAdmin(c,
+		func(s string) (string, error) { return s, nil },
+		func(s string) string { return s }))
+	return c
+}()
+
+// oauthStateTTL bounds how long a consent page may sit open.
+const oauthStateTTL = 10 * time.Minute
+
+// newOAuthProviders reads provider credentials from the environment:
+// IRIS_OAUTH_{GOOGLE,GITHUB}_CLIENT_ID and _CLIENT_SECRET, plus
+// IRIS_OAUTH_REDIRECT_BASE, the externally visible base URL callbacks
+// are registered under.
+func newOAuthProviders() error {
+	base := strings.TrimSuffix(os.Getenv("IRIS_OAUTH_REDIRECT_BASE"), "/")
+	add := func(name string, endpoint oauth2.Endpoint, scopes []string,
+		userInfo func(context.Context, *http.Client) (string, string, error)) error {
+		id := os.Getenv("IRIS_OAUTH_" + strings.ToUpper(name) + "_CLIENT_ID")
+		secret := os.Getenv("IRIS_OAUTH_" + strings.ToUpper(name) + "_CLIENT_SECRET")
+		if id == "" && secret == "" {
+			return nil
+		}
+		if id == "" || secret == "" || base == "" {
+			return fmt.Errorf("oauth %s needs client id, secret, and IRIS_OAUTH_REDIRECT_BASE", name)
+		}
+		oauthProviders[name] = &oauthProvider{
+			config: &oauth2.Config{
+				ClientID:     id,
+				ClientSecret: secret,
+				Endpoint:     endpoint,
+				RedirectURL:  base + "/auth/oauth/" + name + "/callback",
+				Scopes:       scopes,
+			},
+			userInfo: userInfo,
+		}
+		return nil
+	}
+	if err := add("google", google.Endpoint,
+		[]string{"https://www.googleapis.com/auth/userinfo.email", "https://www.googleapis.com/auth/userinfo.profile"},
+		googleUserInfo); err != nil {
+		return err
+	}
+	return add("github", github.Endpoint, []string{"read:user", "user:email"}, githubUserInfo)
+}
+
+func googleUserInfo(ctx context.Context, client *http.Client) (string, string, error) {
+	resp, err := client.Get("https://www.googleapis.com/oauth2/v2/userinfo")
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	var info struct {
+		Email string `json:"email"`
+		Name  string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", "", err
+	}
+	return info.Email, info.Name, nil
+}
+
+func githubUserInfo(ctx context.Context, client *http.Client) (string, string, error) {
+	resp, err := client.Get("https://api.github.com/user")
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	var info struct {
+		Email string `json:"email"`
+		Name  string `json:"name"`
+		Login string `json:"login"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", "", err
+	}
+	if info.Name == "" {
+		info.Name = info.Login
+	}
+	if info.Email != "" {
+		return info.Email, info.Name, nil
+	}
+	// The profile email can be private; the emails endpoint still
+	// reports the verified primary address.
+	resp, err = client.Get("https://api.github.com/user/emails")
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&emails); err != nil {
+		return "", "", err
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			return e.Email, info.Name, nil
+		}
+	}
+	return "", "", errors.New("no verified primary email")
+}
+
+// oauthRoutes dispatches /auth/oauth/{provider} (start) and
+// /auth/oauth/{provider}/callback (finish).
+func oauthRoutes(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/auth/oauth/")
+	parts := strings.Split(rest, "/")
+	p, ok := oauthProviders[parts[0]]
+	if !ok {
+		apiError(w, r, http.StatusNotFound, "unknown oauth provider")
+		return
+	}
+	switch {
+	case len(parts) == 1:
+		oauthStart(w, r, p)
+	case len(parts) == 2 && parts[1] == "callback":
+		oauthCallback(w, r, p)
+	default:
+		respondNotFound(w, r)
+	}
+}
+
+// oauthStart records a state nonce and redirects to the provider's
+// consent page.
+func oauthStart(w http.ResponseWriter, r *http.Request, p *oauthProvider) {
+	state := store.NewUUID()
+	oauthStates.Set(state, struct{}{}, oauthStateTTL)
+	http.Redirect(w, r, p.config.AuthCodeURL(state), http.StatusFound)
+}
+
+// oauthCallback exchanges the code, resolves the provider identity to
+// a local user (creating one on first login), and issues the normal
+// credentials.
+func oauthCallback(w http.ResponseWriter, r *http.Request, p *oauthProvider) {
+	state := r.URL.Query().Get("state")
+	_, known := oauthStates.Get(state)
+	oauthStates.Delete(state)
+	if !known {
+		apiError(w, r, http.StatusBadRequest, "invalid oauth state")
+		return
+	}
+
+	token, err := p.config.Exchange(r.Context(), r.URL.Query().Get("code"))
+	if err != nil {
+		apiError(w, r, http.StatusBadGateway, "oauth exchange failed")
+		return
+	}
+	email, name, err := p.userInfo(r.Context(), p.config.Client(r.Context(), token))
+	if err != nil || email == "" {
+		log.Printf("oauthCallback: fetching user info: %v", err)
+		apiError(w, r, http.StatusBadGateway, "could not fetch user info")
+		return
+	}
+
+	u, err := users.GetByEmail(r.Context(), email)
+	if errors.Is(err, store.ErrNotFound) {
+		u, err = users.Create(r.Context(), models.User{Name: name, Email: email})
+	}
+	if err != nil {
+		apiError(w, r, http.StatusInternalServerError, "internal error")
+		return
+	}
+	issueCredentials(w, r, u)
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/problem"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// The OpenAPI document is assembled once on first request: the model
+// schemas come from the structs via reflection so they cannot drift,
+// while the path table is maintained by hand alongside apiV1.
+var (
+	openapiOnce sync.Once
+	openapiJSON []byte
+)
+
+// schemaFor derives an OpenAPI schema from a Go type, honoring json
+// tags the same way encoding/json does.
+func schemaFor(t reflect.Type) map[string]any {
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := schemaFor(t.Elem())
+		s["nullable"] = true
+		return s
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaFor(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaFor(t.Elem())}
+	case reflect.Struct:
+		if t == reflect.TypeOf(time.Time{}) {
+			return map[string]any{"type": "string", "format": "date-time"}
+		}
+		props := map[string]any{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = schemaFor(f.Type)
+		}
+		return map[string]any{"type": "object", "properties": props}
+	default:
+		return map[string]any{}
+	}
+}
+
+// userOp is shorthand for an operation answering with one user.
+func userOp(summary string) map[string]any {
+	return map[string]any{
+		"summary": summary,
+		"responses": map[string]any{
+			"200": jsonResponse("The user", "#/components/schemas/User"),
+			"404": problemResponse(),
+		},
+	}
+}
+
+func jsonResponse(desc, ref string) map[string]any {
+	return map[string]any{
+		"description": desc,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": map[string]any{"$ref": ref},
+			},
+		},
+	}
+}
+
+func problemResponse() map[string]any {
+	return map[string]any{
+		"description": "Error",
+		"content": map[string]any{
+			"application/problem+json": map[string]any{
+				"schema": map[string]any{"$ref": "#/components/schemas/Problem"},
+			},
+		},
+	}
+}
+
+func buildOpenAPI() map[string]any {
+	userSchema := schemaFor(reflect.TypeOf(models.User{}))
+	// The API never returns the hash (scrubUser clears it) and only
+	// accepts the plaintext password on input.
+	props := userSchema["properties"].(map[string]any)
+	delete(props, "password_hash")
+	if pw, ok := props["password"].(map[string]any); ok {
+		pw["writeOnly"] = true
+	}
+
+	userRef := map[string]any{"$ref": "#/components/schemas/User"}
+	userBody := map[string]any{
+		"required": true,
+		"content":  map[string]any{"application/json": map[string]any{"schema": userRef}},
+	}
+	idParam := map[string]any{
+		"name": "id", "in": "path", "required": true,
+		"schema": map[string]any{"type": "integer"},
+	}
+	qParam := func(name, typ, desc string) map[string]any {
+		return map[string]any{
+			"name": name, "in": "query", "description": desc,
+			"schema": map[string]any{"type": typ},
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "Iris user API",
+			"description": "Backend companion to the Iris web experience.",
+			"version":     "1.0.0",
+		},
+		"servers": []any{map[string]any{"url": "/v1"}},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"User":    userSchema,
+				"Problem": schemaFor(reflect.TypeOf(problem.Problem{})),
+			},
+			"securitySchemes": map[string]any{
+				"bearer": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		"paths": map[string]any{
+			"/users": map[string]any{
+				"get": map[string]any{
+					"summary": "List users",
+					"parameters": []any{
+						qParam("limit", "integer", "page size"),
+						qParam("offset", "integer", "page start"),
+						qParam("sort", "string", "comma-separated fields, '-' for descending"),
+						qParam("name_contains", "string", "case-insensitive name filter"),
+						qParam("email_contains", "string", "case-insensitive email filter"),
+						qParam("age_gte", "integer", "minimum age"),
+						qParam("age_lte", "integer", "maximum age"),
+						qParam("include_deleted", "boolean", "also return soft-deleted users"),
+					},
+					"responses": map[string]any{
+						"200": map[string]any{
+							"description": "A page of users with pagination _links",
+							"content": map[string]any{
+								"application/json": map[string]any{
+									"schema": map[string]any{
+										"type": "object",
+										"properties": map[string]any{
+											"items":  map[string]any{"type": "array", "items": userRef},
+											"_links": map[string]any{"type": "object"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				"post": map[string]any{
+					"summary":     "Create a user",
+					"requestBody": userBody,
+					"responses": map[string]any{
+						"201": jsonResponse("The created user (Location header points at it)", "#/components/schemas/User"),
+						"409": problemResponse(),
+					},
+				},
+				"delete": map[string]any{
+					"summary":    "Bulk delete by ?ids=1,2,3 or a JSON array body",
+					"parameters": []any{qParam("ids", "string", "comma-separated user IDs")},
+					"responses": map[string]any{
+						"200": map[string]any{"description": "Per-ID outcomes, all deleted"},
+						"207": map[string]any{"description": "Per-ID outcomes, some failed"},
+					},
+				},
+			},
+			"/users/{id}": map[string]any{
+				"parameters": []any{idParam},
+				"get":        userOp("Get a user by ID or UUID"),
+				"put": map[string]any{
+					"summary":     "Replace a user",
+					"requestBody": userBody,
+					"responses": map[string]any{
+						"200": jsonResponse("The updated user", "#/components/schemas/User"),
+						"404": problemResponse(),
+					},
+				},
+				"patch": map[string]any{
+					"summary": "Patch a user (RFC 7386 merge patch, or RFC 6902 with application/json-patch+json)",
+					"responses": map[string]any{
+						"200": jsonResponse("The patched user", "#/components/schemas/User"),
+						"404": problemResponse(),
+						"409": problemResponse(),
+						"422": problemResponse(),
+					},
+				},
+				"delete": map[string]any{
+					"summary": "Soft-delete a user",
+					"responses": map[string]any{
+						"204": map[string]any{"description": "Deleted"},
+						"404": problemResponse(),
+					},
+				},
+			},
+			"/users/{id}/restore": map[string]any{
+				"post": map[string]any{
+					"summary":    "Restore a soft-deleted user",
+					"parameters": []any{idParam},
+					"responses": map[string]any{
+						"200": jsonResponse("The restored user", "#/components/schemas/User"),
+						"404": problemResponse(),
+					},
+				},
+			},
+			"/users/by-email/{email}": map[string]any{
+				"get": map[string]any{
+					"summary": "Get a user by email",
+					"parameters": []any{map[string]any{
+						"name": "email", "in": "path", "required": true,
+						"schema": map[string]any{"type": "string"},
+					}},
+					"responses": map[string]any{
+						"200": jsonResponse("The user", "#/components/schemas/User"),
+						"404": problemResponse(),
+					},
+				},
+			},
+			"/users/search": map[string]any{
+				"get": map[string]any{
+					"summary": "Fuzzy-search users by name or email",
+					"parameters": []any{
+						qParam("q", "string", "search query"),
+						qParam("limit", "integer", "maximum results"),
+					},
+					"responses": map[string]any{
+						"200": map[string]any{
+							"description": "Matching users, best first",
+							"content": map[string]any{
+								"application/json": map[string]any{
+									"schema": map[string]any{"type": "array", "items": userRef},
+								},
+							},
+						},
+					},
+				},
+			},
+			"/users/stats": map[string]any{
+				"get": map[string]any{
+					"summary": "Aggregate stats: totals, age distribution, signups per day",
+					"responses": map[string]any{
+						"200": map[string]any{"description": "The aggregates"},
+					},
+				},
+			},
+			"/users/import": map[string]any{
+				"post": map[string]any{
+					"summary": "Bulk import users (NDJSON, or CSV with text/csv)",
+					"responses": map[string]any{
+						"200": map[string]any{"description": "Import report"},
+						"207": map[string]any{"description": "Import report with per-row failures"},
+					},
+				},
+			},
+			"/users/export": map[string]any{
+				"get": map[string]any{
+					"summary":    "Stream all users as NDJSON or CSV",
+					"parameters": []any{qParam("format", "string", "csv or ndjson")},
+					"responses": map[string]any{
+						"200": map[string]any{"description": "The export stream"},
+					},
+				},
+			},
+			"/auth/login": map[string]any{
+				"post": map[string]any{
+					"summary": "Exchange email and password for credentials",
+					"responses": map[string]any{
+						"200": map[string]any{"description": "Access and refresh tokens"},
+						"204": map[string]any{"description": "Session cookie set"},
+						"401": problemResponse(),
+					},
+				},
+			},
+			"/auth/refresh": map[string]any{
+				"post": map[string]any{
+					"summary": "Rotate a refresh token",
+					"responses": map[string]any{
+						"200": map[string]any{"description": "Fresh access and refresh tokens"},
+						"401": problemResponse(),
+					},
+				},
+			},
+			"/auth/logout": map[string]any{
+				"post": map[string]any{
+					"summary": "Destroy the session or revoke the refresh token family",
+					"responses": map[string]any{
+						"204": map[string]any{"description": "Logged out"},
+					},
+				},
+			},
+		},
+		"security": []any{map[string]any{"bearer": []any{}}},
+	}
+}
+
+// serveOpenAPI serves the generated document at /openapi.json.
+func serveOpenAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	openapiOnce.Do(func() {
+		openapiJSON, _ = json.Marshal(buildOpenAPI())
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openapiJSON)
+}
+
+// docsHTML is the Swagger UI shell; the UI assets load from the
+// swagger-ui-dist CDN so the binary stays lean.
+const docsHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Iris API docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// serveDocs serves the Swagger UI explorer at /docs.
+func serveDocs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsHTML))
+}
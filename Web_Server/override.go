@@ -0,0 +1,33 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+)
+
+// methodOverrides are the methods a POST may masquerade as through
+// X-HTTP-Method-Override. GET stays off the list so reads cannot be
+// smuggled past caches, and only POST bodies carry override headers —
+// proxies that block PUT/PATCH/DELETE still allow POST through.
+var methodOverrides = map[string]bool{
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// withMethodOverride rewrites a POST into the method named by its
+// X-HTTP-Method-Override header before routing, so clients behind
+// method-stripping proxies keep full access to the API.
+func withMethodOverride(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if override := r.Header.Get("X-HTTP-Method-Override"); override != "" && r.Method == http.MethodPost {
+			method := strings.ToUpper(strings.TrimSpace(override))
+			if !methodOverrides[method] {
+				apiError(w, r, http.StatusBadRequest, "unsupported method override")
+				return
+			}
+			r.Method = method
+		}
+		next.ServeHTTP(w, r)
+	})
+}
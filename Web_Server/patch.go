@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/divijg19/Iris/Web_Server/internal/cache"
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// errPatchTest distinguishes a failed JSON Patch "test" op, which
+// answers 409 rather than 400.
+var errPatchTest = errors.New("test operation failed")
+
+// patchLocks serializes patches per user ID. A patch is a
+// read-modify-write, and two running concurrently against the same
+// user would each apply to the document the other is about to
+// replace; striping keeps patches to different users contention-free.
+var patchLocks = cache.NewKeyLock[int]()
+
+// patchUser handles PATCH /users/{id}. The default body format is RFC
+// 7386 JSON Merge Patch; "application/json-patch+json" switches to
+// RFC 6902 JSON Patch. Either way the patch is applied to the user's
+// document, the result validated, and the update written through the
+// store like a PUT.
+func patchUser(w http.ResponseWriter, r *http.Request, u models.User) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		if writeBodyTooLarge(w, err) {
+			return
+		}
+		apiError(w, r, http.StatusBadRequest, "reading body")
+		return
+	}
+
+	patchLocks.Lock(u.ID)
+	defer patchLocks.Unlock(u.ID)
+	// The copy routed here was read before the lock; re-read so the
+	// patch applies to whatever the last holder wrote.
+	switch fresh, err := users.Get(r.Context(), u.ID); {
+	case errors.Is(err, store.ErrNotFound):
+		apiError(w, r, http.StatusNotFound, "user not found")
+		return
+	case err != nil:
+		apiStoreError(w, r, err)
+		return
+	default:
+		u = fresh
+	}
+
+	doc := userDoc(u)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json-patch+json") {
+		doc, err = applyJSONPatch(doc, body)
+	} else {
+		doc, err = applyMergePatch(doc, body)
+	}
+	if errors.Is(err, errPatchTest) {
+		apiError(w, r, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		apiError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	in, err := userFromDoc(doc)
+	if err != nil {
+		apiError(w, r, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Identity and credentials are not patchable.
+	in.ID = u.ID
+	in.UUID = u.UUID
+	in.PasswordHash = u.PasswordHash
+	in.DeletedAt = u.DeletedAt
+	in = sanitizeUser(in)
+	if err := validateImport(in); err != nil {
+		apiError(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	if err := users.Update(r.Context(), in); err != nil {
+		apiStoreError(w, r, err)
+		return
+	}
+	recordAudit(r, "update", u.ID, userDiff(u, in))
+	emitUserEvent("user.updated", in)
+	w.Header().Set("ETag", userETag(in))
+	respond.JSON(w, http.StatusOK, withUserLinks(r, scrubUser(in)))
+}
+
+// userDoc renders the patchable view of a user as a generic JSON
+// document.
+func userDoc(u models.User) map[string]any {
+	raw, _ := json.Marshal(scrubUser(u))
+	var doc map[string]any
+	json.Unmarshal(raw, &doc)
+	return doc
+}
+
+// userFromDoc converts a patched document back into the model.
+func userFromDoc(doc map[string]any) (models.User, error) {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return models.User{}, err
+	}
+	var u models.User
+	if err := json.Unmarshal(raw, &u); err != nil {
+		return models.User{}, fmt.Errorf("patched document is not a valid user: %w", err)
+	}
+	return u, nil
+}
+
+// applyMergePatch implements RFC 7386 over a flat user document:
+// present keys replace, null keys delete.
+func applyMergePatch(doc map[string]any, body []byte) (map[string]any, error) {
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal(body, &patch); err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	for key, raw := range patch {
+		if string(raw) == "null" {
+			delete(doc, key)
+			continue
+		}
+		var val any
+		if err := json.Unmarshal(raw, &val); err != nil {
+			return nil, fmt.Errorf("invalid merge patch value for %q: %w", key, err)
+		}
+		doc[key] = val
+	}
+	return doc, nil
+}
+
+// patchOp is one RFC 6902 operation.
+type patchOp struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyJSONPatch implements the RFC 6902 operations over the flat
+// user document. Paths address top-level fields ("/name"); the user
+// model has no nested structure to descend into.
+func applyJSONPatch(doc map[string]any, body []byte) (map[string]any, error) {
+	var ops []patchOp
+	if err := json.Unmarshal(body, &ops); err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+	for i, op := range ops {
+		key, err := pointerKey(op.Path)
+		if err != nil && op.Op != "" {
+			return nil, fmt.Errorf("op %d: %w", i, err)
+		}
+		var val any
+		if len(op.Value) > 0 {
+			if err := json.Unmarshal(op.Value, &val); err != nil {
+				return nil, fmt.Errorf("op %d: invalid value: %w", i, err)
+			}
+		}
+		switch op.Op {
+		case "add", "replace":
+			doc[key] = val
+		case "remove":
+			if _, ok := doc[key]; !ok {
+				return nil, fmt.Errorf("op %d: no value at %s", i, op.Path)
+			}
+			delete(doc, key)
+		case "test":
+			got, _ := json.Marshal(doc[key])
+			want, _ := json.Marshal(val)
+			if string(got) != string(want) {
+				return nil, fmt.Errorf("%w at %s", errPatchTest, op.Path)
+			}
+		case "copy", "move":
+			from, err := pointerKey(op.From)
+			if err != nil {
+				return nil, fmt.Errorf("op %d: %w", i, err)
+			}
+			src, ok := doc[from]
+			if !ok {
+				return nil, fmt.Errorf("op %d: no value at %s", i, op.From)
+			}
+			doc[key] = src
+			if op.Op == "move" {
+				delete(doc, from)
+			}
+		default:
+			return nil, fmt.Errorf("op %d: unsupported op %q", i, op.Op)
+		}
+	}
+	return doc, nil
+}
+
+// pointerKey resolves a single-level JSON Pointer to its field name.
+func pointerKey(path string) (string, error) {
+	key, ok := strings.CutPrefix(path, "/")
+	if !ok || key == "" || strings.Contains(key, "/") {
+		return "", fmt.Errorf("unsupported path %q", path)
+	}
+	key = strings.ReplaceAll(key, "~1", "/")
+	return strings.ReplaceAll(key, "~0", "~"), nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/telemetry"
+)
+
+// proxyRoutes are the configured upstreams, parsed in main so a bad
+// route is a startup error rather than a broken path at runtime.
+var proxyRoutes []*proxyRoute
+
+// errUpstreamDown is the transport's fail-fast result while a circuit
+// is open.
+var errUpstreamDown = errors.New("proxy: upstream circuit open")
+
+// proxyRoute forwards one path prefix to a legacy upstream.
+// IRIS_PROXY_ROUTES lists them as "prefix=url,prefix=url"; the prefix
+// is stripped before forwarding, X-Forwarded-* headers are rewritten,
+// and the Host header becomes the upstream's. IRIS_PROXY_RETRIES
+// (default 2) re-sends idempotent requests after transport failures,
+// and IRIS_PROXY_BREAK_AFTER consecutive failures (default 5) open
+// the route's breaker for IRIS_PROXY_BREAK_FOR (default 30s).
+type proxyRoute struct {
+	prefix  string
+	target  *url.URL
+	retries int
+	breaker *breaker
+}
+
+// newProxyRoutes parses the proxy configuration; an empty
+// IRIS_PROXY_ROUTES means no proxying at all.
+func newProxyRoutes() ([]*proxyRoute, error) {
+	raw := os.Getenv("IRIS_PROXY_ROUTES")
+	if raw == "" {
+		return nil, nil
+	}
+	retries := 2
+	if v := os.Getenv("IRIS_PROXY_RETRIES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid IRIS_PROXY_RETRIES %q", v)
+		}
+		retries = n
+	}
+	breakAfter := 5
+	if v := os.Getenv("IRIS_PROXY_BREAK_AFTER"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid IRIS_PROXY_BREAK_AFTER %q", v)
+		}
+		breakAfter = n
+	}
+	breakFor := 30 * time.Second
+	if v := os.Getenv("IRIS_PROXY_BREAK_FOR"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid IRIS_PROXY_BREAK_FOR %q", v)
+		}
+		breakFor = d
+	}
+
+	var routes []*proxyRoute
+	for _, pair := range strings.Split(raw, ",") {
+		prefix, rawURL, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return nil, fmt.Errorf("invalid IRIS_PROXY_ROUTES entry %q", pair)
+		}
+		target, err := url.Parse(rawURL)
+		if err != nil || target.Scheme == "" || target.Host == "" {
+			return nil, fmt.Errorf("invalid IRIS_PROXY_ROUTES upstream %q", rawURL)
+		}
+		routes = append(routes, &proxyRoute{
+			prefix:  strings.TrimSuffix(prefix, "/"),
+			target:  target,
+			retries: retries,
+			breaker: &breaker{threshold: breakAfter, cooldown: breakFor},
+		})
+	}
+	return routes, nil
+}
+
+// handler builds the ReverseProxy for one route. Transport failures
+// become 502s; an open breaker answers 503 without touching the
+// upstream.
+func (p *proxyRoute) handler() http.Handler {
+	rp := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(p.target)
+			pr.Out.Host = p.target.Host
+			pr.SetXForwarded()
+		},
+		Transport: &retryTransport{
+			base:    telemetry.Transport(nil),
+			retries: p.retries,
+			breaker: p.breaker,
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			if errors.Is(err, errUpstreamDown) {
+				apiError(w, r, http.StatusServiceUnavailable, "upstream unavailable")
+				return
+			}
+			log.Printf("proxy: %s: %v", p.target.Host, err)
+			apiError(w, r, http.StatusBadGateway, "upstream request failed")
+		},
+	}
+	return http.StripPrefix(p.prefix, rp)
+}
+
+// retryTransport re-sends failed requests when that is safe and feeds
+// the route's circuit breaker.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	breaker *breaker
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.breaker.allow() {
+		return nil, errUpstreamDown
+	}
+	var resp *http.Response
+	var err error
+	for attempt := 0; attempt <= t.retries; attempt++ {
+		if attempt > 0 {
+			if !retryable(req) {
+				break
+			}
+			if req.GetBody != nil {
+				body, berr := req.GetBody()
+				if berr != nil {
+					break
+				}
+				req.Body = body
+			}
+		}
+		resp, err = t.base.RoundTrip(req)
+		if err == nil {
+			t.breaker.record(true)
+			return resp, nil
+		}
+	}
+	t.breaker.record(false)
+	return nil, err
+}
+
+// retryable limits retries to idempotent requests whose body, if any,
+// can be replayed. A write that failed mid-flight may have landed.
+func retryable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// breaker is a minimal per-upstream circuit breaker: threshold
+// consecutive transport failures open it for cooldown, during which
+// requests fail fast instead of queueing on a dead upstream.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !time.Now().Before(b.openUntil)
+}
+
+func (b *breaker) record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = time.Now().Add(b.cooldown)
+		b.failures = 0
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/divijg19/Iris/Web_Server/internal/ratelimit"
+)
+
+// limiter is the process-wide request limiter; nil when rate limiting
+// is disabled. Package scope so a config reload can retune it.
+var limiter ratelimit.Store
+
+// trustedProxies are the CIDR ranges whose X-Forwarded-For headers we
+// believe, parsed from IRIS_TRUSTED_PROXIES and swapped wholesale on
+// config reload.
+var trustedProxies = struct {
+	mu   sync.RWMutex
+	nets []*net.IPNet
+}{}
+
+// applyTrustedProxies rebuilds the trusted proxy list from
+// IRIS_TRUSTED_PROXIES (comma-separated CIDRs allowed to set
+// X-Forwarded-For). Safe to call again on reload.
+func applyTrustedProxies() error {
+	var nets []*net.IPNet
+	for _, cidr := range strings.Split(os.Getenv("IRIS_TRUSTED_PROXIES"), ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		_, ipnet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid IRIS_TRUSTED_PROXIES entry %q", cidr)
+		}
+		nets = append(nets, ipnet)
+	}
+	trustedProxies.mu.Lock()
+	trustedProxies.nets = nets
+	trustedProxies.mu.Unlock()
+	return nil
+}
+
+// rateSettings parses IRIS_RATE_LIMIT (requests/second, disabled when
+// unset) and IRIS_RATE_BURST (default 2x the rate).
+func rateSettings() (rate float64, burst int, enabled bool, err error) {
+	raw := os.Getenv("IRIS_RATE_LIMIT")
+	if raw == "" {
+		return 0, 0, false, nil
+	}
+	rate, err = strconv.ParseFloat(raw, 64)
+	if err != nil || rate <= 0 {
+		return 0, 0, false, fmt.Errorf("invalid IRIS_RATE_LIMIT %q", raw)
+	}
+	burst = int(math.Ceil(rate * 2))
+	if v := os.Getenv("IRIS_RATE_BURST"); v != "" {
+		burst, err = strconv.Atoi(v)
+		if err != nil || burst < 1 {
+			return 0, 0, false, fmt.Errorf("invalid IRIS_RATE_BURST %q", v)
+		}
+	}
+	return rate, burst, true, nil
+}
+
+// newRateLimiter builds the request limiter from the environment,
+// using IRIS_RATE_REDIS to share the buckets via Redis when set, and
+// parses the trusted proxy list as a side effect.
+func newRateLimiter() (ratelimit.Store, error) {
+	if err := applyTrustedProxies(); err != nil {
+		return nil, err
+	}
+	rate, burst, enabled, err := rateSettings()
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, nil
+	}
+	if addr := os.Getenv("IRIS_RATE_REDIS"); addr != "" {
+		return ratelimit.NewRedis(context.Background(), addr, rate, burst)
+	}
+	return ratelimit.NewMemory(rate, burst), nil
+}
+
+// retuneRateLimiter pushes reloaded IRIS_RATE_LIMIT/IRIS_RATE_BURST
+// values into a running in-memory limiter. Turning limiting on or
+// off, or switching backends, still takes a restart — the middleware
+// chain is built once.
+func retuneRateLimiter() error {
+	mem, ok := limiter.(*ratelimit.Memory)
+	if !ok {
+		return nil
+	}
+	rate, burst, enabled, err := rateSettings()
+	if err != nil {
+		return err
+	}
+	if enabled {
+		mem.SetLimit(rate, burst)
+	}
+	return nil
+}
+
+func isTrustedProxy(ip net.IP) bool {
+	trustedProxies.mu.RLock()
+	defer trustedProxies.mu.RUnlock()
+	for _, n := range trustedProxies.nets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP extracts the real client address: the rightmost
+// X-Forwarded-For hop not belonging to a trusted proxy when the
+// request came through one, otherwise the peer address itself.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	peer := net.ParseIP(host)
+	if peer == nil || !isTrustedProxy(peer) {
+		return host
+	}
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if ip := net.ParseIP(hop); ip != nil && !isTrustedProxy(ip) {
+			return hop
+		}
+	}
+	return host
+}
+
+// withRateLimit wraps the whole mux with the per-IP limiter; with no
+// limiter configured it is a no-op.
+func withRateLimit(limiter ratelimit.Store, next http.Handler) http.Handler {
+	if limiter == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok, retryAfter, err := limiter.Allow(r.Context(), clientIP(r))
+		if err != nil {
+			// A broken limiter backend should not take the API down.
+			log.Printf("ratelimit: %v", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !ok {
+			secs := int(math.Ceil(retryAfter.Seconds()))
+			if secs < 1 {
+				secs = 1
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+			apiError(w, r, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+)
+
+// panicReporter, when set, forwards recovered panics to an external
+// error tracker alongside the local log line.
+var panicReporter func(r *http.Request, recovered any)
+
+// withRecovery converts handler panics into a structured 500 instead
+// of a dropped connection. Each request gets an ID (the caller's
+// X-Request-ID when present, a fresh UUID otherwise) that is echoed
+// on the response and stamped into the stack-trace log line, so a
+// client report can be matched to its trace.
+func withRecovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("X-Request-ID")
+		if id == "" {
+			id = store.NewUUID()
+		}
+		w.Header().Set("X-Request-ID", id)
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				// The server uses this sentinel to abort cleanly;
+				// suppressing it would break that contract.
+				panic(recovered)
+			}
+			log.Printf("iris: level=error msg=\"panic recovered\" request_id=%s method=%s path=%s err=%v\n%s",
+				id, r.Method, r.URL.Path, recovered, debug.Stack())
+			if panicReporter != nil {
+				panicReporter(r, recovered)
+			}
+			apiError(w, r, http.StatusInternalServerError, "internal error")
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
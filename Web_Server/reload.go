@@ -0,0 +1,85 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/divijg19/Iris/Web_Server/internal/audit"
+	"github.com/divijg19/Iris/Web_Server/internal/config"
+	"github.com/divijg19/Iris/Web_Server/internal/redact"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+)
+
+// watchConfig reloads the configuration on SIGHUP. Only the tunables
+// that are consulted per request pick up new values — body limits,
+// cache policies, route timeouts, the sanitizer mode, trusted
+// proxies, and the in-memory rate limiter's rate and burst. Settings
+// that choose listeners, store backends, or auth modes are wired at
+// startup and keep their old values until a restart.
+func watchConfig(cfg *config.Config, path string) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGHUP)
+	go func() {
+		for range ch {
+			reloadConfig(cfg, path)
+		}
+	}()
+}
+
+// reloadConfig re-resolves the layered configuration, re-applies the
+// reloadable tunables, and records an audit entry naming every key
+// that changed. A file that fails to parse or validate is rejected
+// whole; the running configuration stays as it was.
+func reloadConfig(cfg *config.Config, path string) {
+	changed, err := cfg.Reload(path)
+	if err != nil {
+		log.Printf("iris: config reload rejected: %v", err)
+		return
+	}
+	// SIGHUP doubles as the rotation signal for the access log, even
+	// when no setting changed.
+	if accessLog != nil {
+		if err := accessLog.Reopen(); err != nil {
+			log.Printf("iris: config reload: %v", err)
+		}
+	}
+	if len(changed) == 0 {
+		log.Println("iris: config reload: nothing changed")
+		return
+	}
+	for _, apply := range []func() error{
+		applyBodyLimitOverrides,
+		applyCachePolicyOverrides,
+		applyRouteTimeoutOverrides,
+		applySanitizerConfig,
+		applyInflightLimits,
+		applyTrustedProxies,
+		retuneRateLimiter,
+	} {
+		if err := apply(); err != nil {
+			log.Printf("iris: config reload: %v", err)
+		}
+	}
+	diff := make(map[string]audit.Change, len(changed))
+	keys := make([]string, 0, len(changed))
+	for k, c := range changed {
+		keys = append(keys, k)
+		from, to := any(c[0]), any(c[1])
+		if config.Secret(k) {
+			from, to = redact.Mask(), redact.Mask()
+		}
+		diff[k] = audit.Change{From: from, To: to}
+	}
+	sort.Strings(keys)
+	auditLog.Record(audit.Entry{
+		Actor:     "system",
+		Action:    "config.reload",
+		Diff:      diff,
+		RequestID: store.NewUUID(),
+	})
+	log.Printf("iris: config reloaded: %s", strings.Join(keys, ", "))
+}
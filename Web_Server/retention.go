@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+)
+
+// purgedTotal counts users permanently removed by the retention job
+// since startup.
+var purgedTotal atomic.Int64
+
+// startRetentionJob launches the purge loop when IRIS_RETENTION is
+// set to a duration (e.g. "720h"). Soft-deleted users older than that
+// are permanently removed on every sweep; IRIS_RETENTION_INTERVAL
+// (default one hour) sets the sweep cadence and IRIS_RETENTION_DRY_RUN
+// makes the job only report what it would purge.
+func startRetentionJob(st store.Store) {
+	raw := os.Getenv("IRIS_RETENTION")
+	if raw == "" {
+		return
+	}
+	retention, err := time.ParseDuration(raw)
+	if err != nil || retention <= 0 {
+		log.Fatalf("invalid IRIS_RETENTION %q", raw)
+	}
+	purger, ok := st.(store.Purger)
+	if !ok {
+		log.Fatalf("store backend does not support purging")
+	}
+
+	interval := time.Hour
+	if v := os.Getenv("IRIS_RETENTION_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			log.Fatalf("invalid IRIS_RETENTION_INTERVAL %q", v)
+		}
+		interval = d
+	}
+	dryRun := os.Getenv("IRIS_RETENTION_DRY_RUN") == "true"
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			cutoff := time.Now().Add(-retention)
+			n, err := purger.Purge(context.Background(), cutoff, dryRun)
+			if err != nil {
+				log.Printf("retention: purge failed: %v", err)
+				continue
+			}
+			if dryRun {
+				if n > 0 {
+					log.Printf("retention: dry run, would purge %d users deleted before %s", n, cutoff.Format(time.RFC3339))
+				}
+				continue
+			}
+			if n > 0 {
+				total := purgedTotal.Add(int64(n))
+				log.Printf("retention: purged %d users (total %d)", n, total)
+			}
+		}
+	}()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+)
+
+// middleware is one layer of per-route wrapping. protected already
+// has this shape; the *By adapters below give the pattern-keyed
+// wrappers (limited, timed, cached) the same one.
+type middleware = func(http.HandlerFunc) http.HandlerFunc
+
+// router is a thin veneer over ServeMux that adds route groups:
+// subrouters sharing a path prefix and a middleware stack, so related
+// routes are registered once against their group instead of repeating
+// prefix strings and wrapper chains at every call.
+type router struct {
+	mux    *http.ServeMux
+	prefix string
+	mw     []middleware
+}
+
+func newRouter() *router {
+	return &router{mux: http.NewServeMux()}
+}
+
+// Group returns a subrouter rooted at the group's prefix plus prefix,
+// carrying the parent's middleware plus mw. Groups nest.
+func (rt *router) Group(prefix string, mw ...middleware) *router {
+	return &router{
+		mux:    rt.mux,
+		prefix: rt.prefix + prefix,
+		mw:     append(slices.Clone(rt.mw), mw...),
+	}
+}
+
+// Handle registers h at the group's prefix plus pattern, wrapped in
+// the group middleware and then the route's own, outermost first. An
+// empty pattern registers the group root itself.
+func (rt *router) Handle(pattern string, h http.HandlerFunc, mw ...middleware) {
+	stack := append(slices.Clone(rt.mw), mw...)
+	for i := len(stack) - 1; i >= 0; i-- {
+		h = stack[i](h)
+	}
+	rt.mux.HandleFunc(rt.prefix+pattern, h)
+}
+
+// Mux exposes the underlying ServeMux for callers that need the bare
+// handler (mounting, in-process dispatch like /batch).
+func (rt *router) Mux() *http.ServeMux {
+	return rt.mux
+}
+
+// NotFound sends every path the table doesn't claim to the server's
+// not-found fallback, replacing ServeMux's plain-text default.
+func (rt *router) NotFound() {
+	rt.mux.HandleFunc(rt.prefix+"/", respondNotFound)
+}
+
+// limitedBy, timedBy, and cachedBy curry the pattern-keyed wrappers
+// into middleware. The key is the wrapper table's entry, which for
+// grouped routes is usually the shared prefix, not the full pattern.
+func limitedBy(key string) middleware {
+	return func(h http.HandlerFunc) http.HandlerFunc { return limited(key, h) }
+}
+
+func timedBy(key string) middleware {
+	return func(h http.HandlerFunc) http.HandlerFunc { return timed(key, h) }
+}
+
+func cachedBy(key string) middleware {
+	return func(h http.HandlerFunc) http.HandlerFunc { return cached(key, h) }
+}
+
+func guardedBy(key string) middleware {
+	return func(h http.HandlerFunc) http.HandlerFunc { return guarded(key, h) }
+}
+
+// scoped is the protected(requireScope(...)) pair most routes guard
+// themselves with.
+func scoped(scope string) middleware {
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return protected(requireScope(scope, h))
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/divijg19/Iris/Web_Server/internal/sanitize"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// sanitizer cleans user-supplied strings on every create and update.
+// IRIS_SANITIZE_HTML selects the markup treatment for display fields:
+// "escape", "strip", or unset to leave markup in place.
+var sanitizer sanitize.Policy
+
+// applySanitizerConfig reads IRIS_SANITIZE_HTML into sanitizer.
+func applySanitizerConfig() error {
+	switch v := os.Getenv("IRIS_SANITIZE_HTML"); v {
+	case "":
+		sanitizer.HTML = sanitize.HTMLKeep
+	case "escape":
+		sanitizer.HTML = sanitize.HTMLEscape
+	case "strip":
+		sanitizer.HTML = sanitize.HTMLStrip
+	default:
+		return fmt.Errorf("invalid IRIS_SANITIZE_HTML %q", v)
+	}
+	return nil
+}
+
+// sanitizeUser cleans the writable string fields of an incoming user.
+// Name is a display field and gets the full pipeline; Email is an
+// identifier, so it is normalized but never escaped.
+func sanitizeUser(u models.User) models.User {
+	u.Name = sanitizer.Clean(u.Name)
+	u.Email = sanitizer.Normalize(u.Email)
+	return u
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// loadSeed populates the store from a JSON array of users at boot.
+// Records whose email is already present are skipped, so re-running
+// against an existing database is harmless. It returns how many users
+// were created.
+func loadSeed(ctx context.Context, st store.Store, path string) (int, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading seed file: %w", err)
+	}
+	var seed []models.User
+	if err := json.Unmarshal(raw, &seed); err != nil {
+		return 0, fmt.Errorf("parsing seed file %s: %w", path, err)
+	}
+
+	existing, err := st.List(ctx, store.ListOptions{IncludeDeleted: true})
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, u := range existing {
+		seen[u.Email] = true
+	}
+
+	created := 0
+	err = st.WithTx(ctx, func(tx store.Store) error {
+		for _, u := range seed {
+			if seen[u.Email] {
+				continue
+			}
+			if u.Password != "" {
+				hash, err := hashPassword(u.Password)
+				if err != nil {
+					return err
+				}
+				u.PasswordHash = hash
+				u.Password = ""
+			}
+			if _, err := tx.Create(ctx, u); err != nil {
+				return fmt.Errorf("seeding %s: %w", u.Email, err)
+			}
+			seen[u.Email] = true
+			created++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created, nil
+}
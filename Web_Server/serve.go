@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// http2Settings builds the HTTP/2 tuning shared by every listener
+// mode: IRIS_HTTP2_MAX_STREAMS caps concurrent streams per
+// connection and IRIS_HTTP2_IDLE_TIMEOUT closes idle connections.
+func http2Settings() (*http2.Server, error) {
+	h2 := &http2.Server{}
+	if v := os.Getenv("IRIS_HTTP2_MAX_STREAMS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid IRIS_HTTP2_MAX_STREAMS %q", v)
+		}
+		h2.MaxConcurrentStreams = uint32(n)
+	}
+	if v := os.Getenv("IRIS_HTTP2_IDLE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid IRIS_HTTP2_IDLE_TIMEOUT %q", v)
+		}
+		h2.IdleTimeout = d
+	}
+	return h2, nil
+}
+
+// tuneServer applies the connection hardening shared by every
+// listener. The defaults — a 10s header read deadline and a 2m idle
+// timeout — shut out slowloris-style clients without touching the
+// long-running import/export bodies; IRIS_READ_HEADER_TIMEOUT,
+// IRIS_READ_TIMEOUT, IRIS_WRITE_TIMEOUT, IRIS_IDLE_TIMEOUT, and
+// IRIS_MAX_HEADER_BYTES override them (a zero duration disables that
+// deadline).
+func tuneServer(srv *http.Server) error {
+	srv.ReadHeaderTimeout = 10 * time.Second
+	srv.IdleTimeout = 2 * time.Minute
+	for _, knob := range []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"IRIS_READ_HEADER_TIMEOUT", &srv.ReadHeaderTimeout},
+		{"IRIS_READ_TIMEOUT", &srv.ReadTimeout},
+		{"IRIS_WRITE_TIMEOUT", &srv.WriteTimeout},
+		{"IRIS_IDLE_TIMEOUT", &srv.IdleTimeout},
+	} {
+		v := os.Getenv(knob.name)
+		if v == "" {
+			continue
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid %s %q", knob.name, v)
+		}
+		*knob.dst = d
+	}
+	if v := os.Getenv("IRIS_MAX_HEADER_BYTES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid IRIS_MAX_HEADER_BYTES %q", v)
+		}
+		srv.MaxHeaderBytes = n
+	}
+	return nil
+}
+
+// serve runs the listener stack. With no TLS configuration it serves
+// plaintext on :8080 as before. IRIS_TLS_CERT/IRIS_TLS_KEY switch to
+// HTTPS with certificates from disk; IRIS_TLS_AUTOCERT (comma-
+// separated hostnames) provisions them from Let's Encrypt instead,
+// caching under IRIS_TLS_AUTOCERT_CACHE. In either TLS mode the
+// plaintext port stays up only to redirect callers to HTTPS.
+//
+// SIGINT or SIGTERM stops the listeners from accepting and drains
+// in-flight requests for up to IRIS_SHUTDOWN_GRACE (default 15s); a
+// clean drain returns nil so main can flush and close the stores.
+func serve(handler http.Handler) error {
+	grace := 15 * time.Second
+	if v := os.Getenv("IRIS_SHUTDOWN_GRACE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid IRIS_SHUTDOWN_GRACE %q", v)
+		}
+		grace = d
+	}
+
+	certFile := os.Getenv("IRIS_TLS_CERT")
+	keyFile := os.Getenv("IRIS_TLS_KEY")
+
+	h2, err := http2Settings()
+	if err != nil {
+		return err
+	}
+
+	// IRIS_TLS_CLIENT_CA flips either TLS mode into mutual TLS: client
+	// certificates are required, verified against that CA, and their
+	// identity made available through clientCertFrom.
+	clientCAs, err := clientCAPool()
+	if err != nil {
+		return err
+	}
+	if clientCAs != nil {
+		handler = withClientCert(handler)
+	}
+
+	// srv is the primary listener; aux is the plaintext sidekick that
+	// only redirects or answers ACME challenges. start runs srv in the
+	// mode the configuration picked.
+	var srv, aux *http.Server
+	var start func() error
+
+	switch {
+	case os.Getenv("IRIS_TLS_AUTOCERT") != "":
+		var hosts []string
+		for _, h := range strings.Split(os.Getenv("IRIS_TLS_AUTOCERT"), ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				hosts = append(hosts, h)
+			}
+		}
+		cache := os.Getenv("IRIS_TLS_AUTOCERT_CACHE")
+		if cache == "" {
+			cache = "data/autocert"
+		}
+		mgr := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(hosts...),
+			Cache:      autocert.DirCache(cache),
+		}
+		// Port 80 answers the ACME http-01 challenge and redirects
+		// everything else.
+		aux = &http.Server{Addr: ":http", Handler: mgr.HTTPHandler(nil)}
+		srv = newTLSServer(":https", handler, clientCAs)
+		cfg := mgr.TLSConfig()
+		cfg.ClientCAs = srv.TLSConfig.ClientCAs
+		cfg.ClientAuth = srv.TLSConfig.ClientAuth
+		srv.TLSConfig = cfg
+		if err := http2.ConfigureServer(srv, h2); err != nil {
+			return err
+		}
+		log.Printf("iris: listening on :https for %s (autocert)", strings.Join(hosts, ", "))
+		start = func() error { return srv.ListenAndServeTLS("", "") }
+
+	case certFile != "" || keyFile != "":
+		if certFile == "" || keyFile == "" {
+			return errors.New("IRIS_TLS_CERT and IRIS_TLS_KEY must both be set")
+		}
+		addr := os.Getenv("IRIS_TLS_ADDR")
+		if addr == "" {
+			addr = ":8443"
+		}
+		aux = &http.Server{Addr: ":8080", Handler: redirectToTLS(addr)}
+		srv = newTLSServer(addr, handler, clientCAs)
+		if err := http2.ConfigureServer(srv, h2); err != nil {
+			return err
+		}
+		log.Printf("iris: listening on %s (TLS)", addr)
+		start = func() error { return srv.ListenAndServeTLS(certFile, keyFile) }
+
+	default:
+		if clientCAs != nil {
+			return errors.New("IRIS_TLS_CLIENT_CA requires a TLS listener")
+		}
+		addr := os.Getenv("IRIS_ADDR")
+		if addr == "" {
+			addr = ":8080"
+		}
+		// IRIS_H2C upgrades the plaintext listener to cleartext HTTP/2
+		// for deployments behind a proxy or gRPC gateway that speaks h2
+		// without TLS. Browsers never negotiate h2c, so it stays opt-in.
+		if os.Getenv("IRIS_H2C") == "true" {
+			handler = h2c.NewHandler(handler, h2)
+		}
+		// IRIS_ADDR=off drops the TCP listener entirely for pure
+		// sidecar deployments; the Unix socket then carries everything.
+		if addr == "off" {
+			if os.Getenv("IRIS_UNIX_SOCKET") == "" {
+				return errors.New("IRIS_ADDR=off requires IRIS_UNIX_SOCKET")
+			}
+			srv = &http.Server{Handler: handler}
+			break
+		}
+		log.Printf("iris: listening on %s", addr)
+		srv = &http.Server{Addr: addr, Handler: handler}
+		start = srv.ListenAndServe
+	}
+
+	// IRIS_UNIX_SOCKET adds (or, with IRIS_ADDR=off, substitutes) a
+	// Unix domain socket speaking the same handler stack, for sidecar
+	// deployments behind nginx or caddy on the same host.
+	// IRIS_UNIX_SOCKET_MODE sets the socket file's permissions (octal,
+	// default 0660). A stale socket from an unclean exit is removed;
+	// closing the listener unlinks the live one.
+	var unixSrv *http.Server
+	var unixLn net.Listener
+	if path := os.Getenv("IRIS_UNIX_SOCKET"); path != "" {
+		mode := os.FileMode(0o660)
+		if v := os.Getenv("IRIS_UNIX_SOCKET_MODE"); v != "" {
+			n, err := strconv.ParseUint(v, 8, 32)
+			if err != nil || n > 0o777 {
+				return fmt.Errorf("invalid IRIS_UNIX_SOCKET_MODE %q", v)
+			}
+			mode = os.FileMode(n)
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("removing stale socket: %w", err)
+		}
+		unixLn, err = net.Listen("unix", path)
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(path, mode); err != nil {
+			return fmt.Errorf("setting socket mode: %w", err)
+		}
+		unixSrv = &http.Server{Handler: srv.Handler}
+		log.Printf("iris: listening on unix socket %s (mode %04o)", path, mode)
+	}
+
+	for _, s := range []*http.Server{srv, aux, unixSrv} {
+		if s == nil {
+			continue
+		}
+		if err := tuneServer(s); err != nil {
+			return err
+		}
+	}
+
+	errc := make(chan error, 3)
+	if start != nil {
+		go func() { errc <- start() }()
+	}
+	if aux != nil {
+		go func() { errc <- aux.ListenAndServe() }()
+	}
+	if unixSrv != nil {
+		go func() { errc <- unixSrv.Serve(unixLn) }()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Printf("iris: shutting down, draining for up to %s", grace)
+	sctx, cancel := context.WithTimeout(context.Background(), grace)
+	defer cancel()
+	if aux != nil {
+		aux.Shutdown(sctx)
+	}
+	if unixSrv != nil {
+		unixSrv.Shutdown(sctx)
+	}
+	if err := srv.Shutdown(sctx); err != nil {
+		return fmt.Errorf("draining connections: %w", err)
+	}
+	return nil
+}
+
+// newTLSServer builds the HTTPS server shared by both TLS modes,
+// demanding verified client certificates when a CA pool is given.
+func newTLSServer(addr string, handler http.Handler, clientCAs *x509.CertPool) *http.Server {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if clientCAs != nil {
+		cfg.ClientCAs = clientCAs
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return &http.Server{Addr: addr, Handler: handler, TLSConfig: cfg}
+}
+
+// redirectToTLS permanently redirects plaintext requests to the HTTPS
+// listener, preserving host and path.
+func redirectToTLS(tlsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(tlsAddr)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+//go:embed public
+var publicFS embed.FS
+
+// staticStart stamps Last-Modified on embedded files, which carry no
+// modification time of their own; it changes with each deploy, which
+// is exactly when the embedded content can change.
+var staticStart = time.Now()
+
+// staticHandler serves the embedded public/ directory at the site
+// root, so the API and a small frontend ship in one binary. Known
+// files come with their extension's content type; HTML revalidates
+// while assets cache for an hour; and any path that matches no file
+// falls back to index.html so client-side routing survives a reload.
+func staticHandler() http.Handler {
+	sub, err := fs.Sub(publicFS, "public")
+	if err != nil {
+		panic(err) // the embed directive guarantees public/ exists
+	}
+	files := http.FileServer(http.FS(sub))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			respondMethodNotAllowed(w, r)
+			return
+		}
+		name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+		if name == "" || name == "." {
+			name = "index.html"
+		}
+		if _, err := fs.Stat(sub, name); err != nil {
+			serveIndex(w, r, sub)
+			return
+		}
+		if strings.HasSuffix(name, ".html") {
+			w.Header().Set("Cache-Control", "no-cache")
+		} else {
+			w.Header().Set("Cache-Control", "public, max-age=3600")
+		}
+		files.ServeHTTP(w, r)
+	})
+}
+
+// serveIndex is the SPA fallback: the shell page answers for every
+// unknown path, always revalidated so a new deploy shows up.
+func serveIndex(w http.ResponseWriter, r *http.Request, sub fs.FS) {
+	index, err := fs.ReadFile(sub, "index.html")
+	if err != nil {
+		respondNotFound(w, r)
+		return
+	}
+	w.Header().Set("Cache-Control", "no-cache")
+	http.ServeContent(w, r, "index.html", staticStart, bytes.NewReader(index))
+}
+
+// staticOrAPI keeps the unversioned API alias working at the root:
+// paths the route table recognizes go to the API, everything else to
+// the embedded frontend.
+func staticOrAPI(api http.Handler) http.Handler {
+	static := staticHandler()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if allowedMethods(r.URL.Path) != nil {
+			api.ServeHTTP(w, r)
+			return
+		}
+		static.ServeHTTP(w, r)
+	})
+}
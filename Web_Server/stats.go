@@ -0,0 +1,25 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"github.com/divijg19/Iris/Web_Server/internal/store"
+)
+
+// userStats serves GET /users/stats: totals, the age distribution,
+// and signups per day, aggregated in the repository layer. Cache hit
+// rates will join the payload once a caching layer exists to report
+// them.
+func userStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	st, err := store.UserStats(r.Context(), users)
+	if err != nil {
+		apiStoreError(w, r, err)
+		return
+	}
+	respond.JSON(w, http.StatusOK, st)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRouteTimeouts is the built-in per-route request deadline.
+// The bulk routes get room to stream; everything else should answer
+// fast.
+var defaultRouteTimeouts = map[string]time.Duration{
+	"/admin/":       10 * time.Second,
+	"/auth/":        5 * time.Second,
+	"/batch":        30 * time.Second,
+	"/users":        5 * time.Second,
+	"/users/":       10 * time.Second,
+	"/users/export": 2 * time.Minute,
+	"/users/import": 2 * time.Minute,
+}
+
+// routeTimeouts is the live table, swapped wholesale on config
+// reload.
+var routeTimeouts = struct {
+	mu sync.RWMutex
+	m  map[string]time.Duration
+}{m: defaultRouteTimeouts}
+
+// applyRouteTimeoutOverrides rebuilds the table from the defaults
+// plus IRIS_ROUTE_TIMEOUTS ("pattern=duration,..."). Safe to call
+// again on reload.
+func applyRouteTimeoutOverrides() error {
+	m := maps.Clone(defaultRouteTimeouts)
+	if raw := os.Getenv("IRIS_ROUTE_TIMEOUTS"); raw != "" {
+		for _, pair := range strings.Split(raw, ",") {
+			pattern, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return fmt.Errorf("invalid IRIS_ROUTE_TIMEOUTS entry %q", pair)
+			}
+			d, err := time.ParseDuration(val)
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid IRIS_ROUTE_TIMEOUTS duration %q", val)
+			}
+			m[pattern] = d
+		}
+	}
+	routeTimeouts.mu.Lock()
+	routeTimeouts.m = m
+	routeTimeouts.mu.Unlock()
+	return nil
+}
+
+// timed wraps h so its request context carries the deadline
+// registered for pattern. Handlers pass that context into repository
+// calls, so an overrun surfaces as context.DeadlineExceeded and
+// apiStoreError turns it into a structured 504; if the handler gave
+// up without writing anything, the backstop here does it.
+func timed(pattern string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		routeTimeouts.mu.RLock()
+		timeout, ok := routeTimeouts.m[pattern]
+		routeTimeouts.mu.RUnlock()
+		if !ok {
+			h(w, r)
+			return
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		dw := &deadlineWriter{ResponseWriter: w}
+		h(dw, r.WithContext(ctx))
+		if ctx.Err() == context.DeadlineExceeded && !dw.wrote {
+			apiError(w, r, http.StatusGatewayTimeout, "request deadline exceeded")
+		}
+	}
+}
+
+// deadlineWriter records whether the handler committed a response, so
+// the timeout backstop never writes a second status line.
+type deadlineWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (dw *deadlineWriter) WriteHeader(status int) {
+	dw.wrote = true
+	dw.ResponseWriter.WriteHeader(status)
+}
+
+func (dw *deadlineWriter) Write(p []byte) (int, error) {
+	dw.wrote = true
+	return dw.ResponseWriter.Write(p)
+}
+
+func (dw *deadlineWriter) Flush() {
+	if f, ok := dw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/divijg19/Iris/Web_Server/internal/respond"
+	"github.com/divijg19/Iris/Web_Server/internal/webhook"
+	"github.com/divijg19/Iris/Web_Server/models"
+)
+
+// webhooks fans user lifecycle events out to registered endpoints;
+// wired up in main.
+var webhooks *webhook.Dispatcher
+
+// emitUserEvent publishes one lifecycle event with the scrubbed user
+// as payload. A nil dispatcher (tests, subcommands) is a no-op.
+func emitUserEvent(eventType string, u models.User) {
+	// Every mutation funnels through here, so the freshness tracker
+	// piggybacks on the webhook fan-out.
+	touchUser(u.ID)
+	if webhooks == nil {
+		return
+	}
+	webhooks.Emit(eventType, scrubUser(u))
+}
+
+// adminWebhooks serves /admin/webhooks: GET lists endpoints, POST
+// registers one from {"url": ..., "secret": ...}.
+func adminWebhooks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		respond.JSON(w, http.StatusOK, webhooks.Endpoints())
+	case http.MethodPost:
+		var in struct {
+			URL    string `json:"url"`
+			Secret string `json:"secret"`
+		}
+		if !decodeJSON(w, r, &in) {
+			return
+		}
+		u, err := url.Parse(in.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			apiError(w, r, http.StatusBadRequest, "url must be absolute http(s)")
+			return
+		}
+		if in.Secret == "" {
+			apiError(w, r, http.StatusBadRequest, "secret is required")
+			return
+		}
+		ep := webhooks.Register(in.URL, in.Secret)
+		respond.Created(w, "/admin/webhooks/"+strconv.Itoa(ep.ID), ep)
+	default:
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// adminWebhookRoutes dispatches /admin/webhooks/{id} (DELETE) and
+// /admin/webhooks/deliveries (GET).
+func adminWebhookRoutes(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/admin/webhooks/")
+	if rest == "deliveries" {
+		if r.Method != http.MethodGet {
+			apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		ds := webhooks.Deliveries()
+		if ds == nil {
+			ds = []webhook.Delivery{}
+		}
+		respond.JSON(w, http.StatusOK, ds)
+		return
+	}
+	id, err := strconv.Atoi(rest)
+	if err != nil {
+		apiError(w, r, http.StatusBadRequest, "invalid webhook id")
+		return
+	}
+	if r.Method != http.MethodDelete {
+		apiError(w, r, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !webhooks.Remove(id) {
+		apiError(w, r, http.StatusNotFound, "webhook not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}